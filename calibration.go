@@ -0,0 +1,177 @@
+package tdms
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ngyewch/tdms-go/utils"
+	"github.com/samber/oops"
+	"gopkg.in/yaml.v3"
+)
+
+// Calibration holds user-supplied scalers which replace or extend the scalers defined in the file.
+type Calibration struct {
+	Name     string
+	Source   string
+	scalers  map[string][]Scaler
+	pathList []string
+}
+
+func NewCalibration(name string) *Calibration {
+	return &Calibration{
+		Name:    name,
+		scalers: make(map[string][]Scaler),
+	}
+}
+
+// AddScaler adds a scaler for the specified channel. A scaler replaces the file's scaler with the same scale ID.
+func (calibration *Calibration) AddScaler(path string, scaler Scaler) {
+	if _, exists := calibration.scalers[path]; !exists {
+		calibration.pathList = append(calibration.pathList, path)
+	}
+	calibration.scalers[path] = append(calibration.scalers[path], scaler)
+}
+
+// Paths returns the paths of the calibrated channels.
+func (calibration *Calibration) Paths() []string {
+	return calibration.pathList
+}
+
+// Scalers returns the scalers supplied for the specified channel.
+func (calibration *Calibration) Scalers(path string) []Scaler {
+	return calibration.scalers[path]
+}
+
+// Apply returns the scaler chain of the specified channel with the calibration applied.
+// A calibration scaler replaces the scaler with the same scale ID, or is otherwise inserted in scale ID order.
+// The specified scalers are not modified.
+func (calibration *Calibration) Apply(path string, scalers []Scaler) []Scaler {
+	calibrationScalers := calibration.scalers[path]
+	if len(calibrationScalers) == 0 {
+		return scalers
+	}
+	appliedScalers := slices.Clone(scalers)
+	for _, scaler := range calibrationScalers {
+		// scale IDs are not necessarily contiguous, so scalers are matched by scale ID rather than by position
+		index := slices.IndexFunc(appliedScalers, func(appliedScaler Scaler) bool {
+			return (appliedScaler != nil) && (appliedScaler.ScaleId() == scaler.ScaleId())
+		})
+		if index >= 0 {
+			appliedScalers[index] = scaler
+			continue
+		}
+		index = slices.IndexFunc(appliedScalers, func(appliedScaler Scaler) bool {
+			return (appliedScaler != nil) && (appliedScaler.ScaleId() > scaler.ScaleId())
+		})
+		if index < 0 {
+			appliedScalers = append(appliedScalers, scaler)
+		} else {
+			appliedScalers = slices.Insert(appliedScalers, index, scaler)
+		}
+	}
+	return appliedScalers
+}
+
+// Describe returns a description of the scalers supplied for the specified channel.
+func (calibration *Calibration) Describe(path string) string {
+	var descriptions []string
+	for _, scaler := range calibration.scalers[path] {
+		info := scaler.Info()
+		descriptions = append(descriptions, fmt.Sprintf("%s[%d] coefficients=%v", info.Type, info.ScaleId, info.Coefficients))
+	}
+	return strings.Join(descriptions, "; ")
+}
+
+// Label returns the calibration name, or the calibration source if no name was specified.
+func (calibration *Calibration) Label() string {
+	if calibration.Name != "" {
+		return calibration.Name
+	}
+	return calibration.Source
+}
+
+type calibrationFile struct {
+	Name     string                   `yaml:"name"`
+	Channels []calibrationFileChannel `yaml:"channels"`
+}
+
+type calibrationFileChannel struct {
+	Path    string           `yaml:"path"`
+	Group   string           `yaml:"group"`
+	Channel string           `yaml:"channel"`
+	Scales  []map[string]any `yaml:"scales"`
+}
+
+// ReadCalibrationFile reads a calibration from a YAML or JSON file.
+//
+// Each scale is specified using the names of the NI_Scale[n]_* properties with the prefix removed,
+// together with the scale ID, e.g.
+//
+//	name: 2026-03 recalibration
+//	channels:
+//	  - group: Group
+//	    channel: Channel 1
+//	    scales:
+//	      - Scale_ID: 1
+//	        Scale_Type: Linear
+//	        Linear_Input_Source: 0
+//	        Linear_Slope: 2.01
+//	        Linear_Y_Intercept: 0.98
+func ReadCalibrationFile(path string) (*Calibration, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// YAML is a superset of JSON.
+	var f calibrationFile
+	err = yaml.Unmarshal(b, &f)
+	if err != nil {
+		return nil, oops.
+			In("Calibration").
+			With("path", path).
+			Wrapf(err, "invalid calibration file")
+	}
+	calibration := NewCalibration(f.Name)
+	calibration.Source = path
+	for _, channel := range f.Channels {
+		channelPath := channel.Path
+		if channelPath == "" {
+			objectPath := ObjectPath{
+				Group:   channel.Group,
+				Channel: channel.Channel,
+			}
+			if !objectPath.IsChannel() {
+				return nil, oops.
+					In("Calibration").
+					With("path", path).
+					Errorf("channel path, or group and channel names, not specified")
+			}
+			channelPath = objectPath.String()
+		}
+		for _, scale := range channel.Scales {
+			scaleId, hasScaleId, err := utils.GetUint(scale, "Scale_ID")
+			if err != nil {
+				return nil, err
+			}
+			if !hasScaleId {
+				return nil, oops.
+					In("Calibration").
+					With("path", path).
+					With("channelPath", channelPath).
+					Errorf("Scale_ID not specified")
+			}
+			scaler, err := NewScaler(uint32(scaleId), scale)
+			if err != nil {
+				return nil, oops.
+					In("Calibration").
+					With("path", path).
+					With("channelPath", channelPath).
+					Wrapf(err, "invalid scale")
+			}
+			calibration.AddScaler(channelPath, scaler)
+		}
+	}
+	return calibration, nil
+}
@@ -0,0 +1,100 @@
+package tdms
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadCalibrationFile(t *testing.T) {
+	{
+		path := filepath.Join(t.TempDir(), "calibration.yaml")
+		err := os.WriteFile(path, []byte(`
+name: recalibration
+channels:
+  - group: group 1
+    channel: bob's channel
+    scales:
+      - Scale_ID: 1
+        Scale_Type: Linear
+        Linear_Input_Source: 0
+        Linear_Slope: 2
+        Linear_Y_Intercept: 0.5
+`), 0644)
+		if !assert.NoError(t, err) {
+			return
+		}
+		calibration, err := ReadCalibrationFile(path)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "recalibration", calibration.Label())
+			assert.Equal(t, []string{"/'group 1'/'bob''s channel'"}, calibration.Paths())
+
+			scalers := calibration.Apply("/'group 1'/'bob''s channel'", nil)
+			if assert.Len(t, scalers, 1) {
+				v, err := scalers[0].Scale(int16(3))
+				if assert.NoError(t, err) {
+					assert.Equal(t, 6.5, v)
+				}
+			}
+			assert.Empty(t, calibration.Apply("/'group 1'/'channel 2'", nil))
+		}
+	}
+	{
+		path := filepath.Join(t.TempDir(), "calibration.json")
+		err := os.WriteFile(path, []byte(`{"channels": [{"path": "/'group 1'/'channel 1'", "scales": [{"Scale_Type": "Linear"}]}]}`), 0644)
+		if !assert.NoError(t, err) {
+			return
+		}
+		_, err = ReadCalibrationFile(path)
+		assert.Error(t, err)
+	}
+}
+
+func TestCalibrationApply(t *testing.T) {
+	newScaler := func(scaleId uint32, slope float64) Scaler {
+		scaler, err := NewLinearScaler(scaleId, map[string]any{
+			"Linear_Input_Source": uint32(0),
+			"Linear_Slope":        slope,
+			"Linear_Y_Intercept":  0.0,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return scaler
+	}
+	scaleIds := func(scalers []Scaler) []uint32 {
+		var ids []uint32
+		for _, scaler := range scalers {
+			ids = append(ids, scaler.ScaleId())
+		}
+		return ids
+	}
+	path := "/'group'/'channel'"
+	// file scale IDs 0 and 2
+	scalers := []Scaler{newScaler(0, 2), newScaler(2, 10)}
+	{
+		calibration := NewCalibration("")
+		calibration.AddScaler(path, newScaler(1, 3))
+		appliedScalers := calibration.Apply(path, scalers)
+		assert.Equal(t, []uint32{0, 1, 2}, scaleIds(appliedScalers))
+		assert.Same(t, scalers[1], appliedScalers[2])
+		v, err := newReadOptions().applyScalers(appliedScalers, 1)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 60.0, v)
+		}
+		assert.Equal(t, []uint32{0, 2}, scaleIds(scalers))
+	}
+	{
+		calibration := NewCalibration("")
+		calibration.AddScaler(path, newScaler(2, 5))
+		calibration.AddScaler(path, newScaler(3, 7))
+		appliedScalers := calibration.Apply(path, scalers)
+		assert.Equal(t, []uint32{0, 2, 3}, scaleIds(appliedScalers))
+		v, err := newReadOptions().applyScalers(appliedScalers, 1)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 70.0, v)
+		}
+	}
+}
@@ -0,0 +1,399 @@
+package converter
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/ngyewch/tdms-go"
+)
+
+const (
+	// arrowBatchSize is the minimum number of rows of each record batch, except the last.
+	arrowBatchSize = 64 * 1024
+
+	arrowPathMetadataKey    = "tdms_path"
+	arrowUnitMetadataKey    = "unit_string"
+	arrowTimeZoneIdentifier = "UTC"
+)
+
+// arrowColumn holds the values of a channel, or of the time column, that have been read but not yet added to a record batch.
+type arrowColumn struct {
+	sampleSize int
+	pending    []float64
+	// pendingTimes holds the times of the time column, in nanoseconds since the Unix epoch.
+	pendingTimes []int64
+	// remaining is the number of samples not yet read.
+	remaining uint64
+}
+
+func (column *arrowColumn) pendingSamples() int {
+	if column.pendingTimes != nil {
+		return len(column.pendingTimes)
+	}
+	return len(column.pending) / column.sampleSize
+}
+
+func (column *arrowColumn) read(sampleCount int) {
+	column.remaining -= min(column.remaining, uint64(sampleCount))
+}
+
+// arrowWriter collects the samples of the channels into record batches with one column per channel, and a time column if time is included.
+// Like csvWriter, rows are added as soon as every column that is not exhausted has a pending sample. Columns are padded with nulls.
+type arrowWriter struct {
+	schema     *arrow.Schema
+	builder    *array.RecordBuilder
+	columns    []*arrowColumn
+	columnMap  map[string]*arrowColumn
+	timeColumn *arrowColumn
+	timePath   string
+	rows       int
+	writeBatch func(batch arrow.RecordBatch) error
+}
+
+// newArrowWriter returns a writer of the specified channels of the source.
+func newArrowWriter(source *Source, channels []*tdms.Node, writeBatch func(batch arrow.RecordBatch) error) (*arrowWriter, error) {
+	file := source.File
+	options := source.Options
+	writer := &arrowWriter{
+		columnMap:  make(map[string]*arrowColumn),
+		writeBatch: writeBatch,
+	}
+
+	var fields []arrow.Field
+	if options.IncludeTime && (len(channels) > 0) {
+		err := options.checkTimeColumn(channels)
+		if err != nil {
+			return nil, err
+		}
+		timeChannel := source.longestChannel(channels)
+		fields = append(fields, arrow.Field{
+			Name:     timeColumnName,
+			Type:     &arrow.TimestampType{Unit: arrow.Nanosecond, TimeZone: arrowTimeZoneIdentifier},
+			Nullable: true,
+		})
+		writer.timeColumn = &arrowColumn{
+			sampleSize:   1,
+			pendingTimes: make([]int64, 0),
+			remaining:    source.sampleCount(timeChannel),
+		}
+		writer.timePath = timeChannel.Path()
+		writer.columns = append(writer.columns, writer.timeColumn)
+	}
+
+	fieldNames, err := columnNames(channels, writer.timeColumn != nil)
+	if err != nil {
+		return nil, err
+	}
+	for i, channel := range channels {
+		metadataKeys := []string{arrowPathMetadataKey}
+		metadataValues := []string{channel.Path()}
+		unit, err := channel.GetStringOrDefault("unit_string", "")
+		if err != nil {
+			return nil, err
+		}
+		if unit != "" {
+			metadataKeys = append(metadataKeys, arrowUnitMetadataKey)
+			metadataValues = append(metadataValues, unit)
+		}
+		fields = append(fields, arrow.Field{
+			Name:     fieldNames[i],
+			Type:     options.arrowDataType(file, channel),
+			Nullable: true,
+			Metadata: arrow.NewMetadata(metadataKeys, metadataValues),
+		})
+		column := &arrowColumn{
+			sampleSize: max(1, int(file.ChannelArrayDimension(channel.Path()))),
+			remaining:  source.sampleCount(channel),
+		}
+		writer.columns = append(writer.columns, column)
+		writer.columnMap[channel.Path()] = column
+	}
+
+	metadata, err := arrowSchemaMetadata(file, channels, options)
+	if err != nil {
+		return nil, err
+	}
+	writer.schema = arrow.NewSchema(fields, &metadata)
+	writer.builder = array.NewRecordBuilder(memory.DefaultAllocator, writer.schema)
+	return writer, nil
+}
+
+// arrowSchemaMetadata returns the properties of the root, the groups of the channels and the channels, as JSON encoded tdms.PropertyInfo lists keyed by object path.
+func arrowSchemaMetadata(file *tdms.File, channels []*tdms.Node, options Options) (arrow.Metadata, error) {
+	nodes := []*tdms.Node{file.Root()}
+	for _, channel := range channels {
+		if !slices.Contains(nodes, channel.Parent()) {
+			nodes = append(nodes, channel.Parent())
+		}
+	}
+	nodes = append(nodes, channels...)
+	var keys []string
+	var values []string
+	for _, node := range nodes {
+		propertyInfos := make([]tdms.PropertyInfo, 0)
+		for name := range options.properties(node) {
+			propertyInfo, _ := node.PropertyInfo(name)
+			propertyInfos = append(propertyInfos, propertyInfo)
+		}
+		b, err := json.Marshal(propertyInfos)
+		if err != nil {
+			return arrow.Metadata{}, err
+		}
+		keys = append(keys, node.Path())
+		values = append(values, string(b))
+	}
+	return arrow.NewMetadata(keys, values), nil
+}
+
+// arrowDataType returns the Arrow type of the channel's samples, see valueDataType. Array channels are fixed size lists.
+// The samples are read as float64 values, so I64 and U64 samples beyond ±2^53 are rounded even though the column type is an integer type.
+func (options Options) arrowDataType(file *tdms.File, channel *tdms.Node) arrow.DataType {
+	var dataType arrow.DataType
+	switch options.valueDataType(file, channel) {
+	case tdms.DataTypeI8:
+		dataType = arrow.PrimitiveTypes.Int8
+	case tdms.DataTypeI16:
+		dataType = arrow.PrimitiveTypes.Int16
+	case tdms.DataTypeI32:
+		dataType = arrow.PrimitiveTypes.Int32
+	case tdms.DataTypeI64:
+		dataType = arrow.PrimitiveTypes.Int64
+	case tdms.DataTypeU8:
+		dataType = arrow.PrimitiveTypes.Uint8
+	case tdms.DataTypeU16:
+		dataType = arrow.PrimitiveTypes.Uint16
+	case tdms.DataTypeU32:
+		dataType = arrow.PrimitiveTypes.Uint32
+	case tdms.DataTypeU64:
+		dataType = arrow.PrimitiveTypes.Uint64
+	case tdms.DataTypeSingleFloat:
+		dataType = arrow.PrimitiveTypes.Float32
+	default:
+		dataType = arrow.PrimitiveTypes.Float64
+	}
+	arrayDimension := file.ChannelArrayDimension(channel.Path())
+	if arrayDimension > 1 {
+		return arrow.FixedSizeListOf(int32(arrayDimension), dataType)
+	}
+	return dataType
+}
+
+func (writer *arrowWriter) handleChunk(chunk tdms.Chunk) error {
+	for _, channel := range chunk.Channels {
+		column, exists := writer.columnMap[channel.Path]
+		if !exists {
+			continue
+		}
+		column.pending = append(column.pending, channel.Samples...)
+		column.read(channel.SampleCount())
+		if (writer.timeColumn != nil) && (channel.Path == writer.timePath) {
+			for _, absoluteTime := range channel.TimeAxis().AbsoluteTimes() {
+				writer.timeColumn.pendingTimes = append(writer.timeColumn.pendingTimes, absoluteTime.Time().UnixNano())
+			}
+			writer.timeColumn.read(channel.SampleCount())
+		}
+	}
+	writer.appendRows(writer.readyRows(false))
+	if writer.rows >= arrowBatchSize {
+		return writer.flush()
+	}
+	return nil
+}
+
+// readyRows returns the number of rows that can be added. Unless flushing, a row can only be added once every column that is not exhausted has a pending sample.
+func (writer *arrowWriter) readyRows(flush bool) int {
+	rows := -1
+	for _, column := range writer.columns {
+		pendingSamples := column.pendingSamples()
+		switch {
+		case flush:
+			rows = max(rows, pendingSamples)
+		case pendingSamples > 0:
+			if rows < 0 {
+				rows = pendingSamples
+			} else {
+				rows = min(rows, pendingSamples)
+			}
+		case column.remaining > 0:
+			return 0
+		}
+	}
+	return max(rows, 0)
+}
+
+func (writer *arrowWriter) appendRows(rows int) {
+	if rows == 0 {
+		return
+	}
+	for i, column := range writer.columns {
+		builder := writer.builder.Field(i)
+		n := min(rows, column.pendingSamples())
+		if column == writer.timeColumn {
+			timestampBuilder := builder.(*array.TimestampBuilder)
+			for _, t := range column.pendingTimes[:n] {
+				timestampBuilder.Append(arrow.Timestamp(t))
+			}
+			column.pendingTimes = column.pendingTimes[n:]
+		} else {
+			appendArrowValues(builder, column.pending[:n*column.sampleSize], column.sampleSize)
+			column.pending = column.pending[n*column.sampleSize:]
+		}
+		builder.AppendNulls(rows - n)
+	}
+	writer.rows += rows
+}
+
+// flush writes the rows added so far as a record batch.
+func (writer *arrowWriter) flush() error {
+	if writer.rows == 0 {
+		return nil
+	}
+	batch := writer.builder.NewRecordBatch()
+	defer batch.Release()
+	writer.rows = 0
+	return writer.writeBatch(batch)
+}
+
+func (writer *arrowWriter) close() error {
+	writer.appendRows(writer.readyRows(true))
+	err := writer.flush()
+	writer.release()
+	return err
+}
+
+// release releases the record builder, if not yet released.
+func (writer *arrowWriter) release() {
+	if writer.builder != nil {
+		writer.builder.Release()
+		writer.builder = nil
+	}
+}
+
+// arrowGroupWriters collects the samples of each group into record batches, with one arrowWriter per group.
+type arrowGroupWriters struct {
+	writers []*arrowWriter
+}
+
+func newArrowGroupWriters(source *Source, handler func(group *tdms.Node, batch arrow.RecordBatch) error) (*arrowGroupWriters, error) {
+	groupWriters := &arrowGroupWriters{}
+	for _, group := range source.Options.children(source.File.Root()) {
+		var groupChannels []*tdms.Node
+		for _, channel := range source.Channels {
+			if channel.Parent() == group {
+				groupChannels = append(groupChannels, channel)
+			}
+		}
+		if len(groupChannels) == 0 {
+			continue
+		}
+		writer, err := newArrowWriter(source, groupChannels, func(batch arrow.RecordBatch) error {
+			return handler(group, batch)
+		})
+		if err != nil {
+			groupWriters.release()
+			return nil, err
+		}
+		groupWriters.writers = append(groupWriters.writers, writer)
+	}
+	return groupWriters, nil
+}
+
+func (groupWriters *arrowGroupWriters) handleChunk(chunk tdms.Chunk) error {
+	for _, writer := range groupWriters.writers {
+		err := writer.handleChunk(chunk)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (groupWriters *arrowGroupWriters) close() error {
+	for _, writer := range groupWriters.writers {
+		err := writer.close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// release releases the builders of the writers that have not been closed.
+func (groupWriters *arrowGroupWriters) release() {
+	for _, writer := range groupWriters.writers {
+		writer.release()
+	}
+}
+
+// ReadArrowRecordBatches reads the data of the file as Arrow record batches, with one table per group.
+// Each table has one column per channel of the group, and a time column if time is included. Samples that are not scaled keep their native type,
+// except that I64 and U64 samples beyond ±2^53 are rounded, since samples are read as float64 values.
+// The schema metadata holds the properties of the root, the group and the channels, as JSON encoded tdms.PropertyInfo lists keyed by object path.
+// The record batch is released after the handler returns.
+func ReadArrowRecordBatches(file *tdms.File, options Options, handler func(group *tdms.Node, batch arrow.RecordBatch) error) error {
+	source, err := NewSource(file, options)
+	if err != nil {
+		return err
+	}
+	groupWriters, err := newArrowGroupWriters(source, handler)
+	if err != nil {
+		return err
+	}
+	defer groupWriters.release()
+	err = options.readData(file, groupWriters.handleChunk)
+	if err != nil {
+		return err
+	}
+	return groupWriters.close()
+}
+
+func appendArrowValues(builder array.Builder, values []float64, sampleSize int) {
+	switch b := builder.(type) {
+	case *array.FixedSizeListBuilder:
+		for i := 0; i < len(values); i += sampleSize {
+			b.Append(true)
+			appendArrowValues(b.ValueBuilder(), values[i:i+sampleSize], 1)
+		}
+	case *array.Int8Builder:
+		for _, v := range values {
+			b.Append(int8(v))
+		}
+	case *array.Int16Builder:
+		for _, v := range values {
+			b.Append(int16(v))
+		}
+	case *array.Int32Builder:
+		for _, v := range values {
+			b.Append(int32(v))
+		}
+	case *array.Int64Builder:
+		for _, v := range values {
+			b.Append(int64(v))
+		}
+	case *array.Uint8Builder:
+		for _, v := range values {
+			b.Append(uint8(v))
+		}
+	case *array.Uint16Builder:
+		for _, v := range values {
+			b.Append(uint16(v))
+		}
+	case *array.Uint32Builder:
+		for _, v := range values {
+			b.Append(uint32(v))
+		}
+	case *array.Uint64Builder:
+		for _, v := range values {
+			b.Append(uint64(v))
+		}
+	case *array.Float32Builder:
+		for _, v := range values {
+			b.Append(float32(v))
+		}
+	case *array.Float64Builder:
+		b.AppendValues(values, nil)
+	}
+}
@@ -1,39 +1,38 @@
 package converter
 
 import (
+	"bufio"
 	"fmt"
 	"os"
-	"time"
+	"strings"
 
 	"github.com/ngyewch/tdms-go"
 )
 
-func ConvertToCDL(inputFile string, outputFile string) error {
-	tdmsFile, err := tdms.OpenFile(inputFile)
-	if err != nil {
-		return err
-	}
-	defer func(tdmsFile *tdms.File) {
-		_ = tdmsFile.Close()
-	}(tdmsFile)
+var cdlFormat = &format{
+	name:        "cdl",
+	description: "CDL, the text notation of NetCDF",
+	extensions:  []string{".cdl"},
+	options:     []OptionInfo{timeOptionInfo},
+	newWriter: func(source *Source, outputFile string) (Writer, error) {
+		return newSpoolWriter(source, func(spools []*channelSpool) error {
+			return writeCDL(source, outputFile, spools)
+		})
+	},
+}
 
-	datasetMap := make(map[string][]float64)
-	channels := make([]*tdms.Node, 0)
+func init() {
+	RegisterFormat(cdlFormat)
+}
 
-	err = tdmsFile.ReadData(func(chunk tdms.Chunk) error {
-		for _, channel := range chunk.Channels {
-			values, exists := datasetMap[channel.Path]
-			if !exists {
-				channels = append(channels, channel.Node)
-			}
-			datasetMap[channel.Path] = append(values, channel.Samples...)
-		}
-		return nil
-	})
-	if err != nil {
-		return err
-	}
+// ConvertToCDL writes the file as CDL, the text notation of NetCDF. The samples are spooled to a temporary file while the file is read,
+// and streamed to the data section one variable at a time.
+func ConvertToCDL(inputFile string, outputFile string, options Options) error {
+	return Convert(inputFile, outputFile, cdlFormat, options)
+}
 
+func writeCDL(source *Source, outputFile string, spools []*channelSpool) error {
+	options := source.Options
 	f, err := os.Create(outputFile)
 	if err != nil {
 		return err
@@ -41,87 +40,151 @@ func ConvertToCDL(inputFile string, outputFile string) error {
 	defer func(f *os.File) {
 		_ = f.Close()
 	}(f)
+	w := bufio.NewWriter(f)
 
-	_, err = f.WriteString("netcdf data {\n")
+	_, err = w.WriteString("netcdf data {\n")
 	if err != nil {
 		return err
 	}
 
-	_, err = f.WriteString("\tdimensions:\n")
+	_, err = w.WriteString("\tdimensions:\n")
 	if err != nil {
 		return err
 	}
-	for _, channel := range channels {
-		variableName := normalizeNetCDFIdentifier(channel.Name())
-		values := datasetMap[channel.Path()]
-		_, err = f.WriteString(fmt.Sprintf("\t\t%s = %d ;\n", variableName, len(values)))
-		if err != nil {
-			return err
+	for _, spool := range spools {
+		dimensionNames := cdlDimensionNames(spool.node, spool.shape, spool.times != nil)
+		dimensions := spool.dimensions()
+		for i, dimensionName := range dimensionNames {
+			_, err = w.WriteString(fmt.Sprintf("\t\t%s = %d ;\n", dimensionName, dimensions[i]))
+			if err != nil {
+				return err
+			}
 		}
 	}
 
-	_, err = f.WriteString("\tvariables:\n")
+	_, err = w.WriteString("\tvariables:\n")
 	if err != nil {
 		return err
 	}
 
-	for _, channel := range channels {
+	for _, spool := range spools {
+		channel := spool.node
 		variableName := normalizeNetCDFIdentifier(channel.Name())
-		_, err = f.WriteString(fmt.Sprintf("\t\tdouble %s(%s) ;\n", variableName, variableName))
+		dimensionName := cdlDimensionName(channel, spool.times != nil)
+		if spool.times != nil {
+			_, err = w.WriteString(fmt.Sprintf("\t\tdouble %s(%s) ;\n", dimensionName, dimensionName))
+			if err != nil {
+				return err
+			}
+			_, err = w.WriteString(fmt.Sprintf("\t\t\t%s:%s = \"%s\" ;\n", dimensionName, timeUnitsAttributeName, spool.timeUnits))
+			if err != nil {
+				return err
+			}
+		}
+		_, err = w.WriteString(fmt.Sprintf("\t\t%s %s(%s) ;\n", cdlType(options), variableName, strings.Join(cdlDimensionNames(channel, spool.shape, spool.times != nil), ", ")))
 		if err != nil {
 			return err
 		}
 
-		for propertyName, propertyValue := range channel.Properties().All() {
-			convertedPropertyValue := func(propertyValue any) string {
-				switch v := propertyValue.(type) {
-				case time.Time:
-					return v.Format(time.RFC3339)
-				default:
-					return fmt.Sprintf("%v", propertyValue)
-				}
-			}(propertyValue)
-			_, err = f.WriteString(fmt.Sprintf("\t\t\t%s:%s = \"%s\" ;\n", variableName, normalizeNetCDFIdentifier(propertyName), convertedPropertyValue))
+		for propertyName, propertyValue := range options.properties(channel) {
+			_, err = w.WriteString(fmt.Sprintf("\t\t\t%s:%s = \"%s\" ;\n", variableName, normalizeNetCDFIdentifier(propertyName), formatPropertyValue(propertyValue)))
+			if err != nil {
+				return err
+			}
+		}
+		for attributeName, attributeValue := range options.calibrationAttributes(channel.Path()) {
+			_, err = w.WriteString(fmt.Sprintf("\t\t\t%s:%s = \"%s\" ;\n", variableName, attributeName, attributeValue))
 			if err != nil {
 				return err
 			}
 		}
 	}
 
-	_, err = f.WriteString("\tdata:\n")
+	_, err = w.WriteString("\tdata:\n")
 	if err != nil {
 		return err
 	}
-	for _, channel := range channels {
-		variableName := normalizeNetCDFIdentifier(channel.Name())
-		values := datasetMap[channel.Path()]
-		_, err = f.WriteString(fmt.Sprintf("\t\t%s = ", variableName))
+	for _, spool := range spools {
+		if spool.times != nil {
+			err = writeCDLData(w, cdlDimensionName(spool.node, true), spool.times, false)
+			if err != nil {
+				return err
+			}
+		}
+		err = writeCDLData(w, normalizeNetCDFIdentifier(spool.node.Name()), spool.values, options.singlePrecision())
 		if err != nil {
 			return err
 		}
-		for i, v := range values {
-			if i > 0 {
-				_, err = f.WriteString(", ")
+	}
+
+	_, err = w.WriteString("}\n")
+	if err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+// cdlDimensionName returns the name of the channel's dimension, which is also the name of its time coordinate variable if time is included.
+func cdlDimensionName(channel *tdms.Node, hasTime bool) string {
+	variableName := normalizeNetCDFIdentifier(channel.Name())
+	if hasTime {
+		return variableName + timeSuffix
+	}
+	return variableName
+}
+
+// cdlDimensionNames returns the names of the dimensions of the channel's variable: the sample dimension, followed by a dimension for each axis of the sample shape.
+func cdlDimensionNames(channel *tdms.Node, shape []int, hasTime bool) []string {
+	variableName := normalizeNetCDFIdentifier(channel.Name())
+	dimensionNames := []string{cdlDimensionName(channel, hasTime)}
+	for i := range shape {
+		dimensionNames = append(dimensionNames, fmt.Sprintf("%s_dim%d", variableName, i+1))
+	}
+	return dimensionNames
+}
+
+// cdlType returns the CDL type of the channels' variables.
+func cdlType(options Options) string {
+	if options.singlePrecision() {
+		return "float"
+	}
+	return "double"
+}
+
+// writeCDLData writes the values of a variable, reading them back from the spool file a batch at a time. Single precision values are rounded to float32.
+func writeCDLData(w *bufio.Writer, variableName string, values *spoolSequence, singlePrecision bool) error {
+	_, err := w.WriteString(fmt.Sprintf("\t\t%s = ", variableName))
+	if err != nil {
+		return err
+	}
+	first := true
+	err = values.each(1, func(batch []float64) error {
+		for _, v := range batch {
+			if !first {
+				_, err := w.WriteString(", ")
 				if err != nil {
 					return err
 				}
 			}
-			_, err = f.WriteString(fmt.Sprintf("%f", v))
+			first = false
+			if singlePrecision {
+				v = float64(float32(v))
+			}
+			_, err := w.WriteString(fmt.Sprintf("%f", v))
 			if err != nil {
 				return err
 			}
 		}
-		_, err = f.WriteString(fmt.Sprintf(" ;\n"))
-		if err != nil {
-			return err
-		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-
-	_, err = f.WriteString("}\n")
+	_, err = w.WriteString(fmt.Sprintf(" ;\n"))
 	if err != nil {
 		return err
 	}
-
 	return nil
 }
 
@@ -0,0 +1,297 @@
+package converter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ngyewch/tdms-go"
+)
+
+var (
+	csvFormat = &format{
+		name:        "csv",
+		description: "comma-separated values, one column per channel",
+		extensions:  []string{".csv"},
+		options:     []OptionInfo{timeOptionInfo, delimiterOptionInfo, floatFormatOptionInfo, unitsOptionInfo},
+		newWriter: func(source *Source, outputFile string) (Writer, error) {
+			return newCSVWriter(source, outputFile, ',')
+		},
+	}
+	tsvFormat = &format{
+		name:        "tsv",
+		description: "tab-separated values, one column per channel",
+		extensions:  []string{".tsv"},
+		options:     []OptionInfo{timeOptionInfo, delimiterOptionInfo, floatFormatOptionInfo, unitsOptionInfo},
+		newWriter: func(source *Source, outputFile string) (Writer, error) {
+			return newCSVWriter(source, outputFile, '\t')
+		},
+	}
+)
+
+func init() {
+	RegisterFormat(csvFormat)
+	RegisterFormat(tsvFormat)
+}
+
+// ConvertToCSV writes one column per channel, and a time column if time is included, to a comma-separated values file.
+// The rows are written as the file is read, so the samples of a channel are held in memory until the samples of the same rows of the
+// other channels have been read.
+func ConvertToCSV(inputFile string, outputFile string, options Options) error {
+	return Convert(inputFile, outputFile, csvFormat, options)
+}
+
+// ConvertToTSV writes one column per channel, and a time column if time is included, to a tab-separated values file.
+func ConvertToTSV(inputFile string, outputFile string, options Options) error {
+	return Convert(inputFile, outputFile, tsvFormat, options)
+}
+
+// csvColumn holds the cells of a channel, or of the time column, that have been read but not yet written.
+type csvColumn struct {
+	names []string
+	units []string
+	// pending holds the cells of each sample that has not yet been written. It is not capped: it holds the samples read ahead of the
+	// slowest column that is not exhausted, which for channels written in the same segments is at most a segment's worth, but is the
+	// whole channel if the other channels' samples only follow it in later segments.
+	pending [][]string
+	// remaining is the number of samples not yet read.
+	remaining uint64
+}
+
+func (column *csvColumn) exhausted() bool {
+	return (len(column.pending) == 0) && (column.remaining == 0)
+}
+
+func (column *csvColumn) append(cells []string) {
+	column.pending = append(column.pending, cells)
+	column.remaining -= min(column.remaining, 1)
+}
+
+// csvWriter writes rows as soon as every column that is not exhausted has a pending sample, so that
+// only the samples read ahead of the slowest column are held in memory.
+type csvWriter struct {
+	f             *os.File
+	w             *csv.Writer
+	options       Options
+	columns       []*csvColumn
+	columnMap     map[string]*csvColumn
+	timeColumn    *csvColumn
+	timePath      string
+	headerWritten bool
+}
+
+// newCSVWriter returns a writer of delimited values. The delimiter defaults to defaultDelimiter.
+func newCSVWriter(source *Source, outputFile string, defaultDelimiter rune) (*csvWriter, error) {
+	options := source.Options
+	if options.Delimiter == 0 {
+		options.Delimiter = defaultDelimiter
+	}
+	if options.FloatFormat != "" {
+		err := checkFloatFormat(options.FloatFormat)
+		if err != nil {
+			return nil, err
+		}
+	}
+	channels := source.Channels
+	writer := &csvWriter{
+		options:   options,
+		columnMap: make(map[string]*csvColumn),
+	}
+
+	if options.IncludeTime && (len(channels) > 0) {
+		err := options.checkTimeColumn(channels)
+		if err != nil {
+			return nil, err
+		}
+		timeChannel := source.longestChannel(channels)
+		writer.timeColumn = &csvColumn{
+			names:     []string{timeColumnName},
+			units:     []string{""},
+			remaining: source.sampleCount(timeChannel),
+		}
+		writer.timePath = timeChannel.Path()
+		writer.columns = append(writer.columns, writer.timeColumn)
+	}
+
+	names, err := columnNames(channels, writer.timeColumn != nil)
+	if err != nil {
+		return nil, err
+	}
+	for i, channel := range channels {
+		unit, err := channel.GetStringOrDefault("unit_string", "")
+		if err != nil {
+			return nil, err
+		}
+		column := &csvColumn{
+			names:     []string{names[i]},
+			units:     []string{unit},
+			remaining: source.sampleCount(channel),
+		}
+		writer.columns = append(writer.columns, column)
+		writer.columnMap[channel.Path()] = column
+	}
+
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return nil, err
+	}
+	writer.f = f
+	writer.w = csv.NewWriter(f)
+	writer.w.Comma = options.Delimiter
+	return writer, nil
+}
+
+func (writer *csvWriter) WriteChunk(chunk tdms.Chunk) error {
+	for _, channel := range chunk.Channels {
+		column, exists := writer.columnMap[channel.Path]
+		if !exists {
+			continue
+		}
+		sampleSize := channel.SampleSize()
+		if (sampleSize > 1) && (len(column.names) == 1) {
+			name, unit := column.names[0], column.units[0]
+			column.names = make([]string, sampleSize)
+			column.units = make([]string, sampleSize)
+			for i := range sampleSize {
+				column.names[i] = fmt.Sprintf("%s[%d]", name, i)
+				column.units[i] = unit
+			}
+		}
+		for i := range channel.SampleCount() {
+			sample := channel.Sample(i)
+			cells := make([]string, len(sample))
+			for j, value := range sample {
+				cells[j] = writer.formatFloat(value)
+			}
+			column.append(cells)
+		}
+		if (writer.timeColumn != nil) && (channel.Path == writer.timePath) {
+			timeAxis := channel.TimeAxis()
+			if writer.timeColumn.units[0] == "" {
+				writer.timeColumn.units[0] = timeAxis.Units()
+			}
+			for _, relativeTime := range timeAxis.RelativeTimes() {
+				writer.timeColumn.append([]string{strconv.FormatFloat(relativeTime, 'g', -1, 64)})
+			}
+		}
+	}
+	return writer.writeRows(false)
+}
+
+// writeRows writes the pending rows. Unless flushing, a row is only written once every column that is not exhausted has a pending sample.
+func (writer *csvWriter) writeRows(flush bool) error {
+	for {
+		ready := false
+		for _, column := range writer.columns {
+			if len(column.pending) > 0 {
+				ready = true
+			} else if !flush && !column.exhausted() {
+				return nil
+			}
+		}
+		if !ready {
+			return nil
+		}
+		if !writer.headerWritten {
+			err := writer.writeHeader()
+			if err != nil {
+				return err
+			}
+		}
+		var row []string
+		for _, column := range writer.columns {
+			if len(column.pending) > 0 {
+				row = append(row, column.pending[0]...)
+				column.pending = column.pending[1:]
+			} else {
+				row = append(row, make([]string, len(column.names))...)
+			}
+		}
+		err := writer.w.Write(row)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (writer *csvWriter) writeHeader() error {
+	var names []string
+	var units []string
+	for _, column := range writer.columns {
+		names = append(names, column.names...)
+		units = append(units, column.units...)
+	}
+	err := writer.w.Write(names)
+	if err != nil {
+		return err
+	}
+	if writer.options.UnitRow {
+		err = writer.w.Write(units)
+		if err != nil {
+			return err
+		}
+	}
+	writer.headerWritten = true
+	return nil
+}
+
+func (writer *csvWriter) Finish() error {
+	err := writer.writeRows(true)
+	if err != nil {
+		return err
+	}
+	if !writer.headerWritten {
+		err = writer.writeHeader()
+		if err != nil {
+			return err
+		}
+	}
+	writer.w.Flush()
+	return writer.w.Error()
+}
+
+func (writer *csvWriter) Close() error {
+	return writer.f.Close()
+}
+
+// checkFloatFormat checks that the fmt format has exactly one verb, which formats floats, and no argument indexes or * widths.
+func checkFloatFormat(format string) error {
+	verbCount := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		if (i < len(format)) && (format[i] == '%') {
+			continue
+		}
+		for (i < len(format)) && strings.ContainsRune("+-# 0123456789.", rune(format[i])) {
+			i++
+		}
+		if i >= len(format) {
+			return fmt.Errorf("invalid float format %q: incomplete verb", format)
+		}
+		if !strings.ContainsRune("beEfFgGxX", rune(format[i])) {
+			return fmt.Errorf("invalid float format %q: %%%c does not format floats", format, format[i])
+		}
+		verbCount++
+	}
+	if verbCount != 1 {
+		return fmt.Errorf("invalid float format %q: %d verbs, expected 1", format, verbCount)
+	}
+	return nil
+}
+
+func (writer *csvWriter) formatFloat(v float64) string {
+	bitSize := 64
+	if writer.options.singlePrecision() {
+		v = float64(float32(v))
+		bitSize = 32
+	}
+	if writer.options.FloatFormat == "" {
+		return strconv.FormatFloat(v, 'g', -1, bitSize)
+	}
+	return fmt.Sprintf(writer.options.FloatFormat, v)
+}
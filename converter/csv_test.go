@@ -0,0 +1,119 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ngyewch/tdms-go"
+	"github.com/ngyewch/tdms-go/internal/testfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToCSV(t *testing.T) {
+	startTime := tdms.NewTimestamp(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	inputFile := writeTestFile(t,
+		testChannel{group: "g", name: "a", dataType: tdms.DataTypeDoubleFloat, values: []float64{1, 2.5, 3},
+			properties: append(testfile.WaveformProperties(testfile.Timestamp(startTime), 0.5), testfile.Property{Name: "unit_string", Value: "V"})},
+		testChannel{group: "g", name: "b", dataType: tdms.DataTypeI32, values: []int32{4},
+			properties: testfile.WaveformProperties(testfile.Timestamp(startTime), 0.5)},
+	)
+	convert := func(convert func(inputFile string, outputFile string, options Options) error, options Options) (string, error) {
+		outputFile := filepath.Join(t.TempDir(), "output")
+		err := convert(inputFile, outputFile, options)
+		if err != nil {
+			return "", err
+		}
+		b, err := os.ReadFile(outputFile)
+		return string(b), err
+	}
+	{
+		// the shorter channel is padded with empty cells
+		output, err := convert(ConvertToCSV, Options{})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "a,b\n1,4\n2.5,\n3,\n", output)
+		}
+	}
+	{
+		output, err := convert(ConvertToCSV, Options{UnitRow: true, Delimiter: ';', FloatFormat: "%.2f"})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "a;b\nV;\n1.00;4.00\n2.50;\n3.00;\n", output)
+		}
+	}
+	{
+		output, err := convert(ConvertToTSV, Options{UnitRow: true, IncludeTime: true})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "time\ta\tb\nseconds since "+startTime.String()+"\tV\t\n0\t1\t4\n0.5\t2.5\t\n1\t3\t\n", output)
+		}
+	}
+	{
+		for _, floatFormat := range []string{"%d", "%f %f", "value", "%%", "%.2", "%[1]f", "%*f"} {
+			_, err := convert(ConvertToCSV, Options{FloatFormat: floatFormat})
+			assert.Error(t, err, floatFormat)
+		}
+		output, err := convert(ConvertToCSV, Options{FloatFormat: "%+.1e%%"})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "a,b\n+1.0e+00%,+4.0e+00%\n+2.5e+00%,\n+3.0e+00%,\n", output)
+		}
+	}
+}
+
+func TestConvertToCSVColumns(t *testing.T) {
+	startTime := tdms.NewTimestamp(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	convert := func(inputFile string, options Options) (string, error) {
+		outputFile := filepath.Join(t.TempDir(), "output.csv")
+		err := ConvertToCSV(inputFile, outputFile, options)
+		if err != nil {
+			return "", err
+		}
+		b, err := os.ReadFile(outputFile)
+		return string(b), err
+	}
+	{
+		// clashing channel names are qualified with the group name, as is a channel named like the time column
+		inputFile := writeTestFile(t,
+			testChannel{group: "g1", name: "a", dataType: tdms.DataTypeI32, values: []int32{1},
+				properties: testfile.WaveformProperties(testfile.Timestamp(startTime), 1)},
+			testChannel{group: "g2", name: "a", dataType: tdms.DataTypeI32, values: []int32{2},
+				properties: testfile.WaveformProperties(testfile.Timestamp(startTime), 1)},
+			testChannel{group: "g2", name: "time", dataType: tdms.DataTypeI32, values: []int32{3},
+				properties: testfile.WaveformProperties(testfile.Timestamp(startTime), 1)},
+		)
+		output, err := convert(inputFile, Options{})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "g1/a,g2/a,time\n1,2,3\n", output)
+		}
+		output, err = convert(inputFile, Options{IncludeTime: true})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "time,g1/a,g2/a,g2/time\n0,1,2,3\n", output)
+		}
+	}
+	{
+		// the time column spans the longest channel, which is not the first channel
+		inputFile := writeTestFile(t,
+			testChannel{group: "g", name: "a", dataType: tdms.DataTypeI32, values: []int32{1},
+				properties: testfile.WaveformProperties(testfile.Timestamp(startTime), 0.5)},
+			testChannel{group: "g", name: "b", dataType: tdms.DataTypeI32, values: []int32{4, 5, 6},
+				properties: testfile.WaveformProperties(testfile.Timestamp(startTime), 0.5)},
+		)
+		output, err := convert(inputFile, Options{IncludeTime: true})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "time,a,b\n0,1,4\n0.5,,5\n1,,6\n", output)
+		}
+	}
+	{
+		inputFile := writeTestFile(t,
+			testChannel{group: "g", name: "a", dataType: tdms.DataTypeI32, values: []int32{1},
+				properties: testfile.WaveformProperties(testfile.Timestamp(startTime), 0.5)},
+			testChannel{group: "g", name: "b", dataType: tdms.DataTypeI32, values: []int32{2},
+				properties: testfile.WaveformProperties(testfile.Timestamp(startTime.AddSeconds(1)), 0.5)},
+		)
+		_, err := convert(inputFile, Options{IncludeTime: true})
+		assert.ErrorContains(t, err, "different start times")
+		output, err := convert(inputFile, Options{})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "a,b\n1,2\n", output)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package converter
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ngyewch/tdms-go"
+)
+
+// Format is an output format of the converter.
+type Format interface {
+	// Name returns the name of the format, e.g. "hdf5".
+	Name() string
+	Description() string
+	// Extensions returns the file extensions of the format, including the leading dot, e.g. ".h5". The first extension is the preferred one.
+	Extensions() []string
+	// Options returns the options that are specific to the format. The other options apply to all formats.
+	Options() []OptionInfo
+	// NewWriter returns a writer of the source's channels to the output file.
+	NewWriter(source *Source, outputFile string) (Writer, error)
+}
+
+// OptionInfo describes an option that is specific to some formats.
+type OptionInfo struct {
+	// Name is the name of the option, as used by tdms-cli, e.g. "delimiter".
+	Name string `json:"name"`
+	// Type is the type of the option's value: "bool", "char" or "string".
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+var (
+	timeOptionInfo = OptionInfo{
+		Name:        "time",
+		Type:        "bool",
+		Description: "include time coordinates (IncludeTime)",
+	}
+	delimiterOptionInfo = OptionInfo{
+		Name:        "delimiter",
+		Type:        "char",
+		Description: "column delimiter (Delimiter)",
+	}
+	floatFormatOptionInfo = OptionInfo{
+		Name:        "float-format",
+		Type:        "string",
+		Description: "fmt format of the sample values (FloatFormat)",
+	}
+	unitsOptionInfo = OptionInfo{
+		Name:        "units",
+		Type:        "bool",
+		Description: "add a header row with units (UnitRow)",
+	}
+)
+
+// Writer writes the chunks read from a Source to the output of a format.
+type Writer interface {
+	// WriteChunk writes the data of a chunk. Channels that are not part of the source are ignored.
+	WriteChunk(chunk tdms.Chunk) error
+	// Finish completes the output, once every chunk has been written.
+	Finish() error
+	// Close releases the resources of the writer, whether or not the output has been completed.
+	Close() error
+}
+
+// Source is the file being converted, together with the channels that are converted.
+type Source struct {
+	File    *tdms.File
+	Options Options
+	// Channels holds the channels that have samples, in the chosen order.
+	Channels []*tdms.Node
+	// SampleCounts holds the number of samples of each channel.
+	SampleCounts map[string]uint64
+}
+
+// NewSource returns the channels of the file that are converted with the specified options.
+func NewSource(file *tdms.File, options Options) (*Source, error) {
+	channels, sampleCounts, err := options.channels(file)
+	if err != nil {
+		return nil, err
+	}
+	return &Source{
+		File:         file,
+		Options:      options,
+		Channels:     channels,
+		SampleCounts: sampleCounts,
+	}, nil
+}
+
+// sampleCount returns the number of samples of the channel.
+func (source *Source) sampleCount(channel *tdms.Node) uint64 {
+	return source.SampleCounts[channel.Path()]
+}
+
+// longestChannel returns the channel with the most samples, which is the first of them if several channels have as many samples.
+// Its time axis covers the samples of the other channels if the channels have the same start time and increment.
+func (source *Source) longestChannel(channels []*tdms.Node) *tdms.Node {
+	var longest *tdms.Node
+	for _, channel := range channels {
+		if (longest == nil) || (source.sampleCount(channel) > source.sampleCount(longest)) {
+			longest = channel
+		}
+	}
+	return longest
+}
+
+// Read reads the data of the source's file, resampled if resampling is enabled, and writes each chunk with the writer.
+func (source *Source) Read(writer Writer) error {
+	return source.Options.readData(source.File, writer.WriteChunk)
+}
+
+// Convert converts the input file to the output file in the specified format.
+func Convert(inputFile string, outputFile string, format Format, options Options) error {
+	tdmsFile, err := tdms.OpenFile(inputFile)
+	if err != nil {
+		return err
+	}
+	defer func(tdmsFile *tdms.File) {
+		_ = tdmsFile.Close()
+	}(tdmsFile)
+
+	source, err := NewSource(tdmsFile, options)
+	if err != nil {
+		return err
+	}
+	writer, err := format.NewWriter(source, outputFile)
+	if err != nil {
+		return err
+	}
+	err = source.Read(writer)
+	if err == nil {
+		err = writer.Finish()
+	}
+	closeErr := writer.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+var formats []Format
+
+// RegisterFormat makes a format available to Formats, LookupFormat and LookupFormatForFile. It panics if a format of the same name is already registered.
+func RegisterFormat(format Format) {
+	_, exists := LookupFormat(format.Name())
+	if exists {
+		panic(fmt.Sprintf("format %s already registered", format.Name()))
+	}
+	formats = append(formats, format)
+	slices.SortFunc(formats, func(a Format, b Format) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+}
+
+// Formats returns the registered formats, ordered by name.
+func Formats() []Format {
+	return slices.Clone(formats)
+}
+
+// LookupFormat returns the registered format with the specified name.
+func LookupFormat(name string) (Format, bool) {
+	for _, format := range formats {
+		if format.Name() == name {
+			return format, true
+		}
+	}
+	return nil, false
+}
+
+// LookupFormatForFile returns the registered format of the output file's extension. Directory outputs, e.g. data.zarr/, may have a trailing slash.
+func LookupFormatForFile(outputFile string) (Format, bool) {
+	extension := strings.ToLower(filepath.Ext(strings.TrimRight(outputFile, "/")))
+	for _, format := range formats {
+		if slices.Contains(format.Extensions(), extension) {
+			return format, true
+		}
+	}
+	return nil, false
+}
+
+// format is a Format implemented by a writer constructor.
+type format struct {
+	name        string
+	description string
+	extensions  []string
+	options     []OptionInfo
+	newWriter   func(source *Source, outputFile string) (Writer, error)
+}
+
+func (format *format) Name() string {
+	return format.name
+}
+
+func (format *format) Description() string {
+	return format.description
+}
+
+func (format *format) Extensions() []string {
+	return slices.Clone(format.extensions)
+}
+
+func (format *format) Options() []OptionInfo {
+	return slices.Clone(format.options)
+}
+
+func (format *format) NewWriter(source *Source, outputFile string) (Writer, error) {
+	return format.newWriter(source, outputFile)
+}
@@ -0,0 +1,62 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupFormatForFile(t *testing.T) {
+	for outputFile, formatName := range map[string]string{
+		"data.csv":         "csv",
+		"DATA.TSV":         "tsv",
+		"data.parquet":     "parquet",
+		"data.feather":     "arrow",
+		"data.arrow":       "arrow",
+		"data.npy":         "npy",
+		"data.npz":         "npz",
+		"data.zarr":        "zarr",
+		"out/data.zarr/":   "zarr",
+		"out/data.zarr//":  "zarr",
+		"dir.v1/data.h5":   "hdf5",
+		"dir.v1/data.mat":  "mat",
+		"dir.v1/data.cdl":  "cdl",
+		"./data.tdms.csv":  "csv",
+		"data.csv.parquet": "parquet",
+	} {
+		format, exists := LookupFormatForFile(outputFile)
+		if assert.True(t, exists, outputFile) {
+			assert.Equal(t, formatName, format.Name(), outputFile)
+		}
+	}
+	for _, outputFile := range []string{"data", "data.txt", "data.csv/x", "data.zarr/x", "data."} {
+		_, exists := LookupFormatForFile(outputFile)
+		assert.False(t, exists, outputFile)
+	}
+}
+
+func TestFormats(t *testing.T) {
+	formats := Formats()
+	for i := 1; i < len(formats); i++ {
+		assert.Less(t, formats[i-1].Name(), formats[i].Name())
+	}
+	format, exists := LookupFormat("zarr")
+	if assert.True(t, exists) {
+		assert.Equal(t, []string{".zarr"}, format.Extensions())
+		assert.Empty(t, format.Options())
+	}
+	format, exists = LookupFormat("csv")
+	if assert.True(t, exists) {
+		var optionNames []string
+		for _, optionInfo := range format.Options() {
+			optionNames = append(optionNames, optionInfo.Name)
+		}
+		assert.Equal(t, []string{"time", "delimiter", "float-format", "units"}, optionNames)
+	}
+	_, exists = LookupFormat("xlsx")
+	assert.False(t, exists)
+	assert.Panics(t, func() {
+		RegisterFormat(csvFormat)
+	})
+	assert.Len(t, Formats(), len(formats))
+}
@@ -2,39 +2,37 @@ package converter
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/gosimple/slug"
 	"github.com/ngyewch/tdms-go"
 	"github.com/scigolib/hdf5"
 )
 
-func ConvertToHDF5(inputFile string, outputFile string) error {
-	tdmsFile, err := tdms.OpenFile(inputFile)
-	if err != nil {
-		return err
-	}
-	defer func(tdmsFile *tdms.File) {
-		_ = tdmsFile.Close()
-	}(tdmsFile)
+var hdf5Format = &format{
+	name:        "hdf5",
+	description: "HDF5, one dataset per channel",
+	extensions:  []string{".h5", ".hdf5"},
+	options:     []OptionInfo{timeOptionInfo},
+	newWriter: func(source *Source, outputFile string) (Writer, error) {
+		return newSpoolWriter(source, func(spools []*channelSpool) error {
+			return writeHDF5(source, outputFile, spools)
+		})
+	},
+}
 
-	datasetMap := make(map[string][]float64)
-	channels := make([]*tdms.Node, 0)
+func init() {
+	RegisterFormat(hdf5Format)
+}
 
-	err = tdmsFile.ReadData(func(chunk tdms.Chunk) error {
-		for _, channel := range chunk.Channels {
-			values, exists := datasetMap[channel.Path]
-			if !exists {
-				channels = append(channels, channel.Node)
-			}
-			datasetMap[channel.Path] = append(values, channel.Samples...)
-		}
-		return nil
-	})
-	if err != nil {
-		return err
-	}
+// ConvertToHDF5 writes each group to an HDF5 group and each channel to a dataset. The samples are spooled to a temporary file
+// while the file is read, and written one channel at a time. The HDF5 writer can neither write part of a dataset nor write a chunked
+// dataset chunk by chunk, so each channel is written in one piece: the samples of the largest channel must fit in memory.
+func ConvertToHDF5(inputFile string, outputFile string, options Options) error {
+	return Convert(inputFile, outputFile, hdf5Format, options)
+}
 
+func writeHDF5(source *Source, outputFile string, spools []*channelSpool) error {
+	options := source.Options
 	hdf5File, err := hdf5.CreateForWrite(outputFile, hdf5.CreateTruncate)
 	if err != nil {
 		return err
@@ -43,47 +41,103 @@ func ConvertToHDF5(inputFile string, outputFile string) error {
 		_ = hdf5File.Close()
 	}(hdf5File)
 
-	err = createHDF5Groups(hdf5File, tdmsFile.Root())
+	err = createHDF5Groups(hdf5File, source.File.Root(), options)
 	if err != nil {
 		return err
 	}
 
-	for _, channel := range channels {
-		values := datasetMap[channel.Path()]
-		hdf5Path, err := convertTDMSPathToHDFS5Path(channel.Path())
+	for _, spool := range spools {
+		err = writeHDF5Dataset(hdf5File, spool, options)
 		if err != nil {
 			return err
 		}
-		dataset, err := hdf5File.CreateDataset(hdf5Path, hdf5.Float64, []uint64{uint64(len(values))})
+	}
+
+	return nil
+}
+
+// writeHDF5Dataset writes the samples of a spooled channel, and their times if time is included, to datasets.
+// The HDF5 writer only writes whole datasets, even with a chunked layout, so the samples of the channel are held in memory.
+func writeHDF5Dataset(hdf5File *hdf5.FileWriter, spool *channelSpool, options Options) error {
+	channel := spool.node
+	hdf5Path, err := convertTDMSPathToHDFS5Path(channel.Path())
+	if err != nil {
+		return err
+	}
+	var dims []uint64
+	for _, dimension := range spool.dimensions() {
+		dims = append(dims, uint64(dimension))
+	}
+	dataType := hdf5.Float64
+	if options.singlePrecision() {
+		dataType = hdf5.Float32
+	}
+	dataset, err := hdf5File.CreateDataset(hdf5Path, dataType, dims)
+	if err != nil {
+		return err
+	}
+	for propertyName, propertyValue := range options.properties(channel) {
+		err = dataset.WriteAttribute(propertyName, convertPropertyValue(propertyValue))
 		if err != nil {
 			return err
 		}
-		for propertyName, propertyValue := range channel.Properties().All() {
-			switch v := propertyValue.(type) {
-			case time.Time:
-				convertedPropertyValue := v.Format(time.RFC3339)
-				err = dataset.WriteAttribute(propertyName, convertedPropertyValue)
-				if err != nil {
-					return err
-				}
-			default:
-				err = dataset.WriteAttribute(propertyName, propertyValue)
-				if err != nil {
-					return err
-				}
-			}
+	}
+	for attributeName, attributeValue := range options.calibrationAttributes(channel.Path()) {
+		err = dataset.WriteAttribute(attributeName, attributeValue)
+		if err != nil {
+			return err
+		}
+	}
+	if spool.times != nil {
+		// the time dataset is referenced by name. It is not attached as an HDF5 dimension scale, since the HDF5 writer cannot write
+		// the object references of the DIMENSION_LIST attribute.
+		err = dataset.WriteAttribute("time", hdf5Path+timeSuffix)
+		if err != nil {
+			return err
+		}
+	}
+	if options.singlePrecision() {
+		values, err := spool.values.collectFloat32()
+		if err != nil {
+			return err
+		}
+		err = dataset.Write(values)
+		if err != nil {
+			return err
+		}
+	} else {
+		values, err := spool.values.collect()
+		if err != nil {
+			return err
 		}
 		err = dataset.Write(values)
 		if err != nil {
 			return err
 		}
 	}
-
+	if spool.times != nil {
+		timeDataset, err := hdf5File.CreateDataset(hdf5Path+timeSuffix, hdf5.Float64, []uint64{uint64(spool.times.length)})
+		if err != nil {
+			return err
+		}
+		err = timeDataset.WriteAttribute(timeUnitsAttributeName, spool.timeUnits)
+		if err != nil {
+			return err
+		}
+		times, err := spool.times.collect()
+		if err != nil {
+			return err
+		}
+		err = timeDataset.Write(times)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func createHDF5Groups(hdf5File *hdf5.FileWriter, node *tdms.Node) error {
-	for _, childNode := range node.Children() {
+func createHDF5Groups(hdf5File *hdf5.FileWriter, node *tdms.Node, options Options) error {
+	for _, childNode := range options.children(node) {
 		if len(childNode.Children()) > 0 {
 			hdf5Path, err := convertTDMSPathToHDFS5Path(childNode.Path())
 			if err != nil {
@@ -93,13 +147,13 @@ func createHDF5Groups(hdf5File *hdf5.FileWriter, node *tdms.Node) error {
 			if err != nil {
 				return err
 			}
-			for propertyName, propertyValue := range childNode.Properties().All() {
-				err = group.WriteAttribute(propertyName, propertyValue)
+			for propertyName, propertyValue := range options.properties(childNode) {
+				err = group.WriteAttribute(propertyName, convertPropertyValue(propertyValue))
 				if err != nil {
 					return err
 				}
 			}
-			err = createHDF5Groups(hdf5File, childNode)
+			err = createHDF5Groups(hdf5File, childNode, options)
 			if err != nil {
 				return err
 			}
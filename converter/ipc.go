@@ -0,0 +1,137 @@
+package converter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/gosimple/slug"
+	"github.com/ngyewch/tdms-go"
+)
+
+var arrowIPCFormat = &format{
+	name:        "arrow",
+	description: "Apache Arrow IPC (Feather v2), one file per group",
+	extensions:  []string{".arrow", ".feather"},
+	options:     []OptionInfo{timeOptionInfo},
+	newWriter: func(source *Source, outputFile string) (Writer, error) {
+		return newArrowIPCWriter(source, outputFile)
+	},
+}
+
+func init() {
+	RegisterFormat(arrowIPCFormat)
+}
+
+// ConvertToArrowIPC writes each group to an Arrow IPC (Feather v2) file, see ReadArrowRecordBatches.
+// If the selected channels belong to more than one group, each group is written to a separate file, named after the output file and
+// the slug of the group name, e.g. data_group-1.arrow. Groups with the same slug, e.g. "A b" and "a-b", are rejected.
+// The files are not compressed, so that they can be memory mapped.
+func ConvertToArrowIPC(inputFile string, outputFile string, options Options) error {
+	return Convert(inputFile, outputFile, arrowIPCFormat, options)
+}
+
+// arrowIPCWriter writes the record batches of each group to an IPC file, created when the first batch of the group is written.
+type arrowIPCWriter struct {
+	paths        map[*tdms.Node]string
+	groupWriters *arrowGroupWriters
+	files        []*os.File
+	ipcWriters   map[*tdms.Node]*ipc.FileWriter
+}
+
+func newArrowIPCWriter(source *Source, outputFile string) (*arrowIPCWriter, error) {
+	paths, err := arrowIPCPaths(source.Channels, outputFile)
+	if err != nil {
+		return nil, err
+	}
+	writer := &arrowIPCWriter{
+		paths:      paths,
+		ipcWriters: make(map[*tdms.Node]*ipc.FileWriter),
+	}
+	groupWriters, err := newArrowGroupWriters(source, writer.writeBatch)
+	if err != nil {
+		return nil, err
+	}
+	writer.groupWriters = groupWriters
+	return writer, nil
+}
+
+// arrowIPCPaths returns the path of the file of each group of the channels: the output file if the channels belong to a single group,
+// and otherwise the output file suffixed with the slug of the group name.
+func arrowIPCPaths(channels []*tdms.Node, outputFile string) (map[*tdms.Node]string, error) {
+	var groups []*tdms.Node
+	for _, channel := range channels {
+		if !slices.Contains(groups, channel.Parent()) {
+			groups = append(groups, channel.Parent())
+		}
+	}
+	paths := make(map[*tdms.Node]string)
+	if len(groups) == 1 {
+		paths[groups[0]] = outputFile
+		return paths, nil
+	}
+	extension := filepath.Ext(outputFile)
+	groupPaths := make(map[string]*tdms.Node)
+	for _, group := range groups {
+		path := strings.TrimSuffix(outputFile, extension) + "_" + slug.Make(group.Name()) + extension
+		other, exists := groupPaths[path]
+		if exists {
+			return nil, fmt.Errorf("groups %s and %s have the same file name %s", other.Path(), group.Path(), path)
+		}
+		groupPaths[path] = group
+		paths[group] = path
+	}
+	return paths, nil
+}
+
+func (writer *arrowIPCWriter) writeBatch(group *tdms.Node, batch arrow.RecordBatch) error {
+	ipcWriter, exists := writer.ipcWriters[group]
+	if !exists {
+		f, err := os.Create(writer.paths[group])
+		if err != nil {
+			return err
+		}
+		writer.files = append(writer.files, f)
+		ipcWriter, err = ipc.NewFileWriter(f, ipc.WithSchema(batch.Schema()))
+		if err != nil {
+			return err
+		}
+		writer.ipcWriters[group] = ipcWriter
+	}
+	return ipcWriter.Write(batch)
+}
+
+func (writer *arrowIPCWriter) WriteChunk(chunk tdms.Chunk) error {
+	return writer.groupWriters.handleChunk(chunk)
+}
+
+func (writer *arrowIPCWriter) Finish() error {
+	err := writer.groupWriters.close()
+	if err != nil {
+		return err
+	}
+	for _, ipcWriter := range writer.ipcWriters {
+		err = ipcWriter.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (writer *arrowIPCWriter) Close() error {
+	writer.groupWriters.release()
+	var err error
+	for _, f := range writer.files {
+		err2 := f.Close()
+		if err == nil {
+			err = err2
+		}
+	}
+	writer.files = nil
+	return err
+}
@@ -0,0 +1,141 @@
+package converter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/ngyewch/tdms-go"
+	"github.com/ngyewch/tdms-go/internal/testfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadArrowRecordBatches(t *testing.T) {
+	file, err := tdms.OpenFile(writeTestFile(t,
+		testChannel{group: "g1", name: "a", dataType: tdms.DataTypeDoubleFloat, values: []float64{1, 2}},
+		testChannel{group: "g1", name: "n", dataType: tdms.DataTypeI32, values: []int32{3, 4}, properties: []testfile.Property{{Name: "gain", Value: int32(2)}}},
+		testChannel{group: "g2", name: "b", dataType: tdms.DataTypeDoubleFloat, values: []float64{5}},
+	))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func(file *tdms.File) {
+		_ = file.Close()
+	}(file)
+
+	schemas := make(map[string]*arrow.Schema)
+	values := make(map[string][]any)
+	err = ReadArrowRecordBatches(file, Options{}, func(group *tdms.Node, batch arrow.RecordBatch) error {
+		schemas[group.Name()] = batch.Schema()
+		for i := range int(batch.NumCols()) {
+			for j := range batch.NumRows() {
+				values[group.Name()] = append(values[group.Name()], batch.Column(i).GetOneForMarshal(int(j)))
+			}
+		}
+		return nil
+	})
+	if !assert.NoError(t, err) || !assert.Len(t, schemas, 2) {
+		return
+	}
+	{
+		schema := schemas["g1"]
+		if assert.Equal(t, 2, schema.NumFields()) {
+			assert.Equal(t, "a", schema.Field(0).Name)
+			assert.Equal(t, arrow.PrimitiveTypes.Float64, schema.Field(0).Type)
+			assert.Equal(t, "n", schema.Field(1).Name)
+			assert.Equal(t, arrow.PrimitiveTypes.Int32, schema.Field(1).Type)
+		}
+		// the properties of the root, the group and its channels, but not of the other group
+		assert.Equal(t, []string{"/", "/'g1'", "/'g1'/'a'", "/'g1'/'n'"}, schema.Metadata().Keys())
+		var propertyInfos []tdms.PropertyInfo
+		assert.NoError(t, json.Unmarshal([]byte(schema.Metadata().Values()[3]), &propertyInfos))
+		assert.Equal(t, []tdms.PropertyInfo{{Name: "gain", Type: "I32", Value: float64(2)}}, propertyInfos)
+		assert.Equal(t, []any{1.0, 2.0, int32(3), int32(4)}, values["g1"])
+	}
+	{
+		schema := schemas["g2"]
+		if assert.Equal(t, 1, schema.NumFields()) {
+			assert.Equal(t, "b", schema.Field(0).Name)
+		}
+		assert.Equal(t, []string{"/", "/'g2'", "/'g2'/'b'"}, schema.Metadata().Keys())
+		assert.Equal(t, []any{5.0}, values["g2"])
+	}
+}
+
+func TestConvertToArrowIPC(t *testing.T) {
+	readRows := func(path string) (int64, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return 0, err
+		}
+		defer func(f *os.File) {
+			_ = f.Close()
+		}(f)
+		reader, err := ipc.NewFileReader(f)
+		if err != nil {
+			return 0, err
+		}
+		defer func(reader *ipc.FileReader) {
+			_ = reader.Close()
+		}(reader)
+		var rows int64
+		for i := range reader.NumRecords() {
+			batch, err := reader.RecordBatch(i)
+			if err != nil {
+				return 0, err
+			}
+			rows += batch.NumRows()
+		}
+		return rows, nil
+	}
+	inputFile := writeTestFile(t,
+		testChannel{group: "g 1", name: "a", dataType: tdms.DataTypeDoubleFloat, values: []float64{1, 2}},
+		testChannel{group: "g2", name: "b", dataType: tdms.DataTypeDoubleFloat, values: []float64{3}},
+	)
+	{
+		// a file per group
+		dir := t.TempDir()
+		err := ConvertToArrowIPC(inputFile, filepath.Join(dir, "data.arrow"), Options{})
+		if assert.NoError(t, err) {
+			rows, err := readRows(filepath.Join(dir, "data_g-1.arrow"))
+			if assert.NoError(t, err) {
+				assert.Equal(t, int64(2), rows)
+			}
+			rows, err = readRows(filepath.Join(dir, "data_g2.arrow"))
+			if assert.NoError(t, err) {
+				assert.Equal(t, int64(1), rows)
+			}
+		}
+	}
+	{
+		// the channels of a single group are written to the output file, although the file has other groups
+		filter, err := tdms.MatchGlob("g2", "*")
+		if !assert.NoError(t, err) {
+			return
+		}
+		dir := t.TempDir()
+		err = ConvertToArrowIPC(inputFile, filepath.Join(dir, "data.arrow"), Options{Include: []tdms.ChannelFilter{filter}})
+		if assert.NoError(t, err) {
+			rows, err := readRows(filepath.Join(dir, "data.arrow"))
+			if assert.NoError(t, err) {
+				assert.Equal(t, int64(1), rows)
+			}
+			_, err = os.Stat(filepath.Join(dir, "data_g2.arrow"))
+			assert.True(t, os.IsNotExist(err))
+		}
+	}
+	{
+		inputFile := writeTestFile(t,
+			testChannel{group: "A b", name: "a", dataType: tdms.DataTypeDoubleFloat, values: []float64{1}},
+			testChannel{group: "a-b", name: "b", dataType: tdms.DataTypeDoubleFloat, values: []float64{2}},
+		)
+		outputFile := filepath.Join(t.TempDir(), "data.arrow")
+		err := ConvertToArrowIPC(inputFile, outputFile, Options{})
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "groups /'A b' and /'a-b' have the same file name")
+		}
+	}
+}
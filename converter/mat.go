@@ -1,38 +1,43 @@
 package converter
 
 import (
+	"fmt"
+
 	"github.com/gosimple/slug"
 	"github.com/ngyewch/tdms-go"
 	"github.com/scigolib/matlab"
 	"github.com/scigolib/matlab/types"
 )
 
-func ConvertToMAT(inputFile string, outputFile string) error {
-	tdmsFile, err := tdms.OpenFile(inputFile)
-	if err != nil {
-		return err
-	}
-	defer func(tdmsFile *tdms.File) {
-		_ = tdmsFile.Close()
-	}(tdmsFile)
+var matFormat = &format{
+	name:        "mat",
+	description: "MATLAB v7.3, one variable per channel",
+	extensions:  []string{".mat"},
+	options:     []OptionInfo{timeOptionInfo},
+	newWriter: func(source *Source, outputFile string) (Writer, error) {
+		return newSpoolWriter(source, func(spools []*channelSpool) error {
+			return writeMAT(source, outputFile, spools)
+		})
+	},
+}
 
-	datasetMap := make(map[string][]float64)
-	channels := make([]*tdms.Node, 0)
+func init() {
+	RegisterFormat(matFormat)
+}
 
-	err = tdmsFile.ReadData(func(chunk tdms.Chunk) error {
-		for _, channel := range chunk.Channels {
-			values, exists := datasetMap[channel.Path]
-			if !exists {
-				channels = append(channels, channel.Node)
-			}
-			datasetMap[channel.Path] = append(values, channel.Samples...)
-		}
-		return nil
-	})
+// ConvertToMAT writes each channel to a MATLAB v7.3 variable, named after its group and channel. The samples are spooled to a temporary file
+// while the file is read, and written one channel at a time. The MAT writer only writes whole variables, so the samples of the
+// largest channel must fit in memory.
+func ConvertToMAT(inputFile string, outputFile string, options Options) error {
+	return Convert(inputFile, outputFile, matFormat, options)
+}
+
+func writeMAT(source *Source, outputFile string, spools []*channelSpool) error {
+	options := source.Options
+	names, err := matVariableNames(spools)
 	if err != nil {
 		return err
 	}
-
 	matFile, err := matlab.Create(outputFile, matlab.Version73)
 	if err != nil {
 		return err
@@ -41,32 +46,83 @@ func ConvertToMAT(inputFile string, outputFile string) error {
 		_ = hdf5File.Close()
 	}(matFile)
 
-	for _, channel := range channels {
-		values := datasetMap[channel.Path()]
+	for i, spool := range spools {
+		channel := spool.node
 		attributes := make(map[string]any)
-		for propertyName, propertyValue := range channel.Properties().All() {
-			/*
-				switch v := propertyValue.(type) {
-					case time.Time:
-						convertedPropertyValue := v.Format(time.RFC3339)
-						attributes[propertyName] = convertedPropertyValue
-				default:
-					attributes[propertyName] = propertyValue
-				}
-			*/
-			attributes[propertyName] = propertyValue
+		for propertyName, propertyValue := range options.properties(channel) {
+			attributes[propertyName] = convertPropertyValue(propertyValue)
 		}
-		err = matFile.WriteVariable(&types.Variable{
-			Name:       slug.Make(channel.Name()),
-			Dimensions: []int{len(values)},
+		for attributeName, attributeValue := range options.calibrationAttributes(channel.Path()) {
+			attributes[attributeName] = attributeValue
+		}
+		variable := &types.Variable{
+			Name:       names[i],
+			Dimensions: spool.dimensions(),
 			DataType:   types.Double,
-			Data:       values,
 			Attributes: attributes,
-		})
+		}
+		if options.singlePrecision() {
+			variable.DataType = types.Single
+			variable.Data, err = spool.values.collectFloat32()
+		} else {
+			variable.Data, err = spool.values.collect()
+		}
 		if err != nil {
 			return err
 		}
+		err = matFile.WriteVariable(variable)
+		if err != nil {
+			return err
+		}
+		if spool.times != nil {
+			times, err := spool.times.collect()
+			if err != nil {
+				return err
+			}
+			err = matFile.WriteVariable(&types.Variable{
+				Name:       names[i] + timeSuffix,
+				Dimensions: []int{len(times)},
+				DataType:   types.Double,
+				Data:       times,
+				Attributes: map[string]any{
+					timeUnitsAttributeName: spool.timeUnits,
+				},
+			})
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
+
+// matVariableNames returns the variable names of the channels. Channels whose names are the same after slugging, including
+// the names of their time variables, are rejected.
+func matVariableNames(spools []*channelSpool) ([]string, error) {
+	names := make([]string, len(spools))
+	variablePaths := make(map[string]string)
+	addVariable := func(name string, channel *tdms.Node) error {
+		path, exists := variablePaths[name]
+		if exists {
+			return fmt.Errorf("channels %s and %s have the same variable name %s", path, channel.Path(), name)
+		}
+		variablePaths[name] = channel.Path()
+		return nil
+	}
+	for i, spool := range spools {
+		channel := spool.node
+		names[i] = slug.Make(channel.Parent().Name()) + "_" + slug.Make(channel.Name())
+		err := addVariable(names[i], channel)
+		if err != nil {
+			return nil, err
+		}
+		if spool.times != nil {
+			err = addVariable(names[i]+timeSuffix, channel)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return names, nil
+}
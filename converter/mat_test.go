@@ -0,0 +1,23 @@
+package converter
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ngyewch/tdms-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMATVariableNames(t *testing.T) {
+	inputFile := writeTestFile(t,
+		testChannel{group: "a", name: "x", dataType: tdms.DataTypeDoubleFloat, values: []float64{1}},
+		testChannel{group: "b", name: "x", dataType: tdms.DataTypeDoubleFloat, values: []float64{2}},
+		testChannel{group: "A", name: "X", dataType: tdms.DataTypeDoubleFloat, values: []float64{3}},
+	)
+	outputFile := filepath.Join(t.TempDir(), "output.mat")
+	err := ConvertToMAT(inputFile, outputFile, Options{})
+	if assert.Error(t, err) {
+		assert.Equal(t, "channels /'a'/'x' and /'A'/'X' have the same variable name a_x", err.Error())
+	}
+	assert.NoFileExists(t, outputFile)
+}
@@ -7,75 +7,200 @@ import (
 	"github.com/ngyewch/tdms-go"
 )
 
-func ConvertToNetCDF4(inputFile string, outputFile string) error {
-	tdmsFile, err := tdms.OpenFile(inputFile)
+var netCDF4Format = &format{
+	name:        "netcdf4",
+	description: "NetCDF-4, one variable per channel",
+	extensions:  []string{".nc"},
+	options:     []OptionInfo{timeOptionInfo},
+	newWriter: func(source *Source, outputFile string) (Writer, error) {
+		return newNetCDF4Writer(source, outputFile)
+	},
+}
+
+func init() {
+	RegisterFormat(netCDF4Format)
+}
+
+// ConvertToNetCDF4 writes each channel to a NetCDF-4 variable. The variables are defined up front from the sample counts of the channels,
+// and the samples are written chunk by chunk as the file is read.
+func ConvertToNetCDF4(inputFile string, outputFile string, options Options) error {
+	return Convert(inputFile, outputFile, netCDF4Format, options)
+}
+
+// netCDF4Writer writes the samples of each chunk to the variables, which are defined up front from the sample counts of the channels.
+type netCDF4Writer struct {
+	ncFile      netcdf.Dataset
+	closed      bool
+	variableMap map[string]*netCDFVariable
+}
+
+func newNetCDF4Writer(source *Source, outputFile string) (*netCDF4Writer, error) {
+	ncFile, err := netcdf.CreateFile(outputFile, netcdf.CLOBBER|netcdf.NETCDF4)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer func(tdmsFile *tdms.File) {
-		_ = tdmsFile.Close()
-	}(tdmsFile)
-
-	datasetMap := make(map[string][]float64)
-	channels := make([]*tdms.Node, 0)
+	writer := &netCDF4Writer{
+		ncFile:      ncFile,
+		variableMap: make(map[string]*netCDFVariable),
+	}
+	for _, channel := range source.Channels {
+		shape := channelSampleShape(source.File, channel)
+		variable, err := addNetCDFVariable(ncFile, channel, datasetDimensions(int(source.SampleCounts[channel.Path()]), shape), source.Options)
+		if err != nil {
+			_ = writer.Close()
+			return nil, err
+		}
+		writer.variableMap[channel.Path()] = variable
+	}
+	return writer, nil
+}
 
-	err = tdmsFile.ReadData(func(chunk tdms.Chunk) error {
-		for _, channel := range chunk.Channels {
-			values, exists := datasetMap[channel.Path]
-			if !exists {
-				channels = append(channels, channel.Node)
+func (writer *netCDF4Writer) WriteChunk(chunk tdms.Chunk) error {
+	for _, channel := range chunk.Channels {
+		variable, exists := writer.variableMap[channel.Path]
+		if !exists || (channel.SampleCount() == 0) {
+			continue
+		}
+		err := variable.write(channel.Samples)
+		if err != nil {
+			return err
+		}
+		if variable.timeVariable != nil {
+			timeAxis := channel.TimeAxis()
+			err = variable.writeTime(timeAxis.Units(), timeAxis.CollectRelativeTimes())
+			if err != nil {
+				return err
 			}
-			datasetMap[channel.Path] = append(values, channel.Samples...)
 		}
+	}
+	return nil
+}
+
+func (writer *netCDF4Writer) Finish() error {
+	return writer.Close()
+}
+
+func (writer *netCDF4Writer) Close() error {
+	if writer.closed {
 		return nil
-	})
-	if err != nil {
-		return err
 	}
+	writer.closed = true
+	return writer.ncFile.Close()
+}
 
-	ncFile, err := netcdf.CreateFile(outputFile, netcdf.CLOBBER|netcdf.NETCDF4)
-	if err != nil {
-		return err
+// channelSampleShape returns the shape of each sample of the channel, or nil for scalar samples.
+func channelSampleShape(tdmsFile *tdms.File, channel *tdms.Node) []int {
+	arrayDimension := tdmsFile.ChannelArrayDimension(channel.Path())
+	if arrayDimension > 1 {
+		return []int{int(arrayDimension)}
 	}
-	defer func(ncFile netcdf.Dataset) {
-		_ = ncFile.Close()
-	}(ncFile)
+	return nil
+}
 
-	for _, channel := range channels {
-		variableName := normalizeNetCDFIdentifier(channel.Name())
-		values := datasetMap[channel.Path()]
-		dims := make([]netcdf.Dim, 1)
-		dims[0], err = ncFile.AddDim(variableName, uint64(len(values)))
+// netCDFVariable writes the samples of a channel, and their times if time is included, to consecutive hyperslabs of its variables.
+type netCDFVariable struct {
+	variable     netcdf.Var
+	timeVariable *netcdf.Var
+	// sampleShape holds the dimensions of the variable after the sample dimension.
+	sampleShape []uint64
+	sampleSize  int
+	// singlePrecision writes the values as FLOAT instead of DOUBLE.
+	singlePrecision bool
+	// float32Values is reused to convert the values of each chunk to float32.
+	float32Values []float32
+	// written and timesWritten are the number of samples and times written so far.
+	written      uint64
+	timesWritten uint64
+}
+
+func addNetCDFVariable(ncFile netcdf.Dataset, channel *tdms.Node, dimensions []int, options Options) (*netCDFVariable, error) {
+	variableName := normalizeNetCDFIdentifier(channel.Name())
+	dimensionNames := cdlDimensionNames(channel, dimensions[1:], options.IncludeTime)
+	dims := make([]netcdf.Dim, len(dimensions))
+	variable := &netCDFVariable{
+		sampleSize:      1,
+		singlePrecision: options.singlePrecision(),
+	}
+	for i, dimension := range dimensions {
+		var err error
+		dims[i], err = ncFile.AddDim(dimensionNames[i], uint64(dimension))
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if i > 0 {
+			variable.sampleShape = append(variable.sampleShape, uint64(dimension))
+			variable.sampleSize *= dimension
 		}
-		v, err := ncFile.AddVar(variableName, netcdf.DOUBLE, dims)
+	}
+	if options.IncludeTime {
+		timeVariable, err := ncFile.AddVar(dimensionNames[0], netcdf.DOUBLE, dims[:1])
 		if err != nil {
-			return err
+			return nil, err
 		}
-		err = v.SetCompression(true, true, 9)
+		variable.timeVariable = &timeVariable
+	}
+	variableType := netcdf.DOUBLE
+	if variable.singlePrecision {
+		variableType = netcdf.FLOAT
+	}
+	v, err := ncFile.AddVar(variableName, variableType, dims)
+	if err != nil {
+		return nil, err
+	}
+	err = v.SetCompression(true, true, 9)
+	if err != nil {
+		return nil, err
+	}
+	for attributeName, attributeValue := range options.calibrationAttributes(channel.Path()) {
+		err = v.Attr(attributeName).WriteBytes([]byte(attributeValue))
 		if err != nil {
-			return err
+			return nil, err
 		}
-		/*
-			for propertyName, propertyValue := range channel.Properties().All() {
-				convertedPropertyValue := func(propertyValue any) string {
-					switch v := propertyValue.(type) {
-					case time.Time:
-						return v.Format(time.RFC3339)
-					default:
-						return fmt.Sprintf("%v", propertyValue)
-					}
-				}(propertyValue)
-				attributeName := normalizeNetCDFIdentifier(propertyName)
-				attribute := v.Attr(attributeName)
-			}
-		*/
-		err = v.WriteFloat64s(values)
+	}
+	variable.variable = v
+	return variable, nil
+}
+
+// write writes the values of the next samples.
+func (variable *netCDFVariable) write(values []float64) error {
+	n := uint64(len(values) / variable.sampleSize)
+	if n == 0 {
+		return nil
+	}
+	start := append([]uint64{variable.written}, make([]uint64, len(variable.sampleShape))...)
+	count := append([]uint64{n}, variable.sampleShape...)
+	var err error
+	if variable.singlePrecision {
+		variable.float32Values = variable.float32Values[:0]
+		for _, v := range values {
+			variable.float32Values = append(variable.float32Values, float32(v))
+		}
+		err = variable.variable.WriteFloat32Slice(variable.float32Values, start, count)
+	} else {
+		err = variable.variable.WriteFloat64Slice(values, start, count)
+	}
+	if err != nil {
+		return err
+	}
+	variable.written += n
+	return nil
+}
+
+// writeTime writes the times of the next samples. The units are written with the first times.
+func (variable *netCDFVariable) writeTime(units string, times []float64) error {
+	if len(times) == 0 {
+		return nil
+	}
+	if variable.timesWritten == 0 {
+		err := variable.timeVariable.Attr(timeUnitsAttributeName).WriteBytes([]byte(units))
 		if err != nil {
 			return err
 		}
 	}
-
+	err := variable.timeVariable.WriteFloat64Slice(times, []uint64{variable.timesWritten}, []uint64{uint64(len(times))})
+	if err != nil {
+		return err
+	}
+	variable.timesWritten += uint64(len(times))
 	return nil
 }
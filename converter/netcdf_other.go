@@ -4,6 +4,6 @@ package converter
 
 import "fmt"
 
-func ConvertToNetCDF4(inputFile string, outputFile string) error {
+func ConvertToNetCDF4(inputFile string, outputFile string, options Options) error {
 	return fmt.Errorf("NetCDF converter not supported on this OS/platform")
 }
@@ -0,0 +1,536 @@
+package converter
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gosimple/slug"
+	"github.com/ngyewch/tdms-go"
+)
+
+const (
+	npyMagic           = "\x93NUMPY"
+	npyPrefixLength    = 10
+	npyHeaderAlignment = 64
+	npyExtension       = ".npy"
+	// npyMaxOpenFiles is the maximum number of .npy files open at a time while writing.
+	npyMaxOpenFiles  = 64
+	sidecarExtension = ".json"
+)
+
+// npySidecar describes the arrays written by ConvertToNPY and ConvertToNPZ, together with the metadata of the file.
+type npySidecar struct {
+	*tdms.FileInfo
+	Arrays []npyArrayInfo `json:"arrays"`
+}
+
+// npyArrayInfo describes the array of a channel.
+type npyArrayInfo struct {
+	Path string `json:"path"`
+	// Name is the name of the .npy file, or the key of the array within the .npz archive.
+	Name  string `json:"name"`
+	DType string `json:"dtype"`
+	Shape []int  `json:"shape"`
+	// FirstSample is the index of the first sample of the array within the channel.
+	FirstSample uint64          `json:"firstSample"`
+	StartTime   *tdms.Timestamp `json:"startTime,omitempty"`
+	Increment   float64         `json:"increment,omitempty"`
+}
+
+var (
+	npyFormat = &format{
+		name:        "npy",
+		description: "NumPy .npy files, one per channel, with a JSON sidecar",
+		extensions:  []string{".npy"},
+		newWriter: func(source *Source, outputFile string) (Writer, error) {
+			return newNPYFilesWriter(source, outputFile)
+		},
+	}
+	npzFormat = &format{
+		name:        "npz",
+		description: "NumPy .npz archive, one array per channel, with a JSON sidecar",
+		extensions:  []string{".npz"},
+		newWriter: func(source *Source, outputFile string) (Writer, error) {
+			return newNPZWriter(source, outputFile)
+		},
+	}
+)
+
+func init() {
+	RegisterFormat(npyFormat)
+	RegisterFormat(npzFormat)
+}
+
+// ConvertToNPY writes each channel to a NumPy .npy file, named after the output file, the group and the channel, e.g. data_group_channel.npy.
+// If there is only one channel, it is written to the output file. Channels whose file names are the same, e.g. the channel c of the groups
+// "A b" and "a-b", are rejected. Samples that are not scaled keep their native type, except that I64 and U64 samples beyond ±2^53 are
+// rounded, since samples are read as float64 values. A JSON sidecar file, e.g. data.json, holds the properties, the waveform attributes and the arrays.
+func ConvertToNPY(inputFile string, outputFile string, options Options) error {
+	return Convert(inputFile, outputFile, npyFormat, options)
+}
+
+// ConvertToNPZ writes all channels to an uncompressed NumPy .npz archive, with one array per channel keyed by group and channel name, e.g. group/channel.
+// Channels with the same key, e.g. the channel b/c of group a and the channel c of group a/b, are rejected.
+// Samples that are not scaled keep their native type, except that I64 and U64 samples beyond ±2^53 are rounded, since samples are read as
+// float64 values. A JSON sidecar file, e.g. data.json, holds the properties, the waveform attributes and the arrays.
+// The arrays are written to temporary files while reading, so that the samples never have to fit in memory.
+func ConvertToNPZ(inputFile string, outputFile string, options Options) error {
+	return Convert(inputFile, outputFile, npzFormat, options)
+}
+
+// npyChannelWriters writes each channel of the source with an npyWriter. At most npyMaxOpenFiles files are kept open at a time,
+// the least recently written file is closed to open another.
+type npyChannelWriters struct {
+	source *Source
+	// names holds the file name, or the archive key, of each channel's array.
+	names     []string
+	nameSet   map[string]bool
+	writers   []*npyWriter
+	writerMap map[string]*npyWriter
+	// openWriters holds the writers with an open file, least recently written first.
+	openWriters []*npyWriter
+}
+
+func newNPYChannelWriters(source *Source) npyChannelWriters {
+	return npyChannelWriters{
+		source:    source,
+		nameSet:   make(map[string]bool),
+		writers:   make([]*npyWriter, 0, len(source.Channels)),
+		writerMap: make(map[string]*npyWriter),
+	}
+}
+
+// add creates the file of the channel's array at the path, and adds its writer. Channels whose arrays have the same name are rejected.
+func (channelWriters *npyChannelWriters) add(path string, channel *tdms.Node, name string) error {
+	if channelWriters.nameSet[name] {
+		return fmt.Errorf("channel %s has the same array name %s as another channel", channel.Path(), name)
+	}
+	writer, err := newNPYWriter(path, channelWriters.source.File, channel, channelWriters.source.Options)
+	if err != nil {
+		return err
+	}
+	channelWriters.nameSet[name] = true
+	channelWriters.writers = append(channelWriters.writers, writer)
+	channelWriters.writerMap[channel.Path()] = writer
+	channelWriters.names = append(channelWriters.names, name)
+	return nil
+}
+
+func (channelWriters *npyChannelWriters) WriteChunk(chunk tdms.Chunk) error {
+	for _, channel := range chunk.Channels {
+		writer, exists := channelWriters.writerMap[channel.Path]
+		if !exists {
+			continue
+		}
+		err := channelWriters.open(writer)
+		if err != nil {
+			return err
+		}
+		err = writer.write(channel)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// open opens the file of the writer, if not open, closing the least recently written file if npyMaxOpenFiles files are open.
+func (channelWriters *npyChannelWriters) open(writer *npyWriter) error {
+	index := slices.Index(channelWriters.openWriters, writer)
+	if index >= 0 {
+		channelWriters.openWriters = append(slices.Delete(channelWriters.openWriters, index, index+1), writer)
+		return nil
+	}
+	if len(channelWriters.openWriters) >= npyMaxOpenFiles {
+		err := channelWriters.openWriters[0].closeFile()
+		if err != nil {
+			return err
+		}
+		channelWriters.openWriters = channelWriters.openWriters[1:]
+	}
+	err := writer.openFile()
+	if err != nil {
+		return err
+	}
+	channelWriters.openWriters = append(channelWriters.openWriters, writer)
+	return nil
+}
+
+// closeWriters flushes the samples and rewrites the headers of the arrays, and writes the sidecar file.
+func (channelWriters *npyChannelWriters) closeWriters(sidecarPath string) error {
+	channelWriters.openWriters = nil
+	for _, writer := range channelWriters.writers {
+		err := writer.close()
+		if err != nil {
+			return err
+		}
+	}
+	source := channelWriters.source
+	return writeNPYSidecar(sidecarPath, source.File, source.Channels, channelWriters.names, channelWriters.writers)
+}
+
+// closeFiles closes the open files, without flushing the samples.
+func (channelWriters *npyChannelWriters) closeFiles() {
+	for _, writer := range channelWriters.openWriters {
+		_ = writer.f.Close()
+		writer.f = nil
+	}
+	channelWriters.openWriters = nil
+}
+
+// npyFilesWriter writes each channel to a .npy file.
+type npyFilesWriter struct {
+	npyChannelWriters
+	sidecarPath string
+}
+
+func newNPYFilesWriter(source *Source, outputFile string) (*npyFilesWriter, error) {
+	extension := filepath.Ext(outputFile)
+	stem := strings.TrimSuffix(outputFile, extension)
+	writer := &npyFilesWriter{
+		npyChannelWriters: newNPYChannelWriters(source),
+		sidecarPath:       stem + sidecarExtension,
+	}
+	for _, channel := range source.Channels {
+		path := outputFile
+		if len(source.Channels) > 1 {
+			path = stem + "_" + slug.Make(channel.Parent().Name()) + "_" + slug.Make(channel.Name()) + npyExtension
+		}
+		err := writer.add(path, channel, filepath.Base(path))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return writer, nil
+}
+
+func (writer *npyFilesWriter) Finish() error {
+	return writer.closeWriters(writer.sidecarPath)
+}
+
+func (writer *npyFilesWriter) Close() error {
+	writer.closeFiles()
+	return nil
+}
+
+// npzWriter writes each channel to a temporary .npy file, and stores the files in the archive once every chunk has been written.
+type npzWriter struct {
+	npyChannelWriters
+	outputFile string
+}
+
+func newNPZWriter(source *Source, outputFile string) (*npzWriter, error) {
+	writer := &npzWriter{
+		npyChannelWriters: newNPYChannelWriters(source),
+		outputFile:        outputFile,
+	}
+	for _, channel := range source.Channels {
+		f, err := os.CreateTemp("", "tdms-*"+npyExtension)
+		if err != nil {
+			_ = writer.Close()
+			return nil, err
+		}
+		_ = f.Close()
+		err = writer.add(f.Name(), channel, channel.Parent().Name()+"/"+channel.Name())
+		if err != nil {
+			_ = os.Remove(f.Name())
+			_ = writer.Close()
+			return nil, err
+		}
+	}
+	return writer, nil
+}
+
+func (writer *npzWriter) Finish() error {
+	extension := filepath.Ext(writer.outputFile)
+	err := writer.closeWriters(strings.TrimSuffix(writer.outputFile, extension) + sidecarExtension)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(writer.outputFile)
+	if err != nil {
+		return err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+	zipWriter := zip.NewWriter(f)
+	for i, npyWriter := range writer.writers {
+		w, err := zipWriter.CreateHeader(&zip.FileHeader{
+			Name:   writer.names[i] + npyExtension,
+			Method: zip.Store,
+		})
+		if err != nil {
+			return err
+		}
+		err = copyFile(w, npyWriter.path)
+		if err != nil {
+			return err
+		}
+	}
+	err = zipWriter.Close()
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Close closes and removes the temporary files.
+func (writer *npzWriter) Close() error {
+	writer.closeFiles()
+	for _, npyWriter := range writer.writers {
+		_ = os.Remove(npyWriter.path)
+	}
+	writer.writers = nil
+	return nil
+}
+
+// copyFile copies the contents of the file at the path to the writer.
+func copyFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+	_, err = io.Copy(w, f)
+	return err
+}
+
+func writeNPYSidecar(path string, tdmsFile *tdms.File, channels []*tdms.Node, names []string, writers []*npyWriter) error {
+	fileInfo, err := tdmsFile.Info()
+	if err != nil {
+		return err
+	}
+	sidecar := npySidecar{
+		FileInfo: fileInfo,
+		Arrays:   make([]npyArrayInfo, 0, len(channels)),
+	}
+	for i, channel := range channels {
+		writer := writers[i]
+		sidecar.Arrays = append(sidecar.Arrays, npyArrayInfo{
+			Path:        channel.Path(),
+			Name:        names[i],
+			DType:       npyDescr(writer.dataType),
+			Shape:       writer.arrayShape(),
+			FirstSample: writer.firstSample,
+			StartTime:   writer.startTime,
+			Increment:   writer.increment,
+		})
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sidecar)
+}
+
+// npyWriter writes the samples of a channel to a version 1.0 .npy file, in little-endian layout.
+// The header reserves space for the largest sample count, and is rewritten with the final shape on close.
+// The file is only open while npyChannelWriters keeps it open.
+type npyWriter struct {
+	path         string
+	f            *os.File
+	w            *bufio.Writer
+	dataType     tdms.DataType
+	sampleShape  []int
+	headerLength int
+	sampleCount  int
+	firstSample  uint64
+	startTime    *tdms.Timestamp
+	increment    float64
+	buffer       []byte
+}
+
+// newNPYWriter creates the file at the path, and writes the header.
+func newNPYWriter(path string, tdmsFile *tdms.File, channel *tdms.Node, options Options) (*npyWriter, error) {
+	writer := &npyWriter{
+		path:     path,
+		dataType: options.valueDataType(tdmsFile, channel),
+	}
+	arrayDimension := tdmsFile.ChannelArrayDimension(channel.Path())
+	if arrayDimension > 1 {
+		writer.sampleShape = []int{int(arrayDimension)}
+	}
+	header := npyHeader(npyDescr(writer.dataType), append([]int{math.MaxInt64}, writer.sampleShape...), 0)
+	writer.headerLength = len(header)
+	err := os.WriteFile(path, header, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// openFile opens the file for appending samples.
+func (writer *npyWriter) openFile() error {
+	f, err := os.OpenFile(writer.path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	_, err = f.Seek(0, io.SeekEnd)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	writer.f = f
+	if writer.w == nil {
+		writer.w = bufio.NewWriter(f)
+	} else {
+		writer.w.Reset(f)
+	}
+	return nil
+}
+
+// closeFile flushes the samples and closes the file, if open.
+func (writer *npyWriter) closeFile() error {
+	if writer.f == nil {
+		return nil
+	}
+	err := writer.w.Flush()
+	closeErr := writer.f.Close()
+	writer.f = nil
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+func (writer *npyWriter) arrayShape() []int {
+	return append([]int{writer.sampleCount}, writer.sampleShape...)
+}
+
+// write appends the samples of the channel. The file must be open.
+func (writer *npyWriter) write(channel tdms.ChannelData) error {
+	if writer.sampleCount == 0 {
+		writer.firstSample = channel.SampleOffset
+		if (channel.WaveformAttributes != nil) && (channel.WaveformAttributes.Increment > 0) {
+			timeAxis := channel.TimeAxis()
+			startTime := timeAxis.AbsoluteTime(0)
+			writer.startTime = &startTime
+			writer.increment = timeAxis.Increment
+		}
+	}
+	writer.buffer = writer.buffer[:0]
+	for _, v := range channel.Samples {
+		writer.buffer = appendNPYValue(writer.buffer, writer.dataType, v)
+	}
+	_, err := writer.w.Write(writer.buffer)
+	if err != nil {
+		return err
+	}
+	writer.sampleCount += channel.SampleCount()
+	return nil
+}
+
+// close flushes the samples, closes the file and rewrites the header.
+func (writer *npyWriter) close() error {
+	err := writer.closeFile()
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(writer.path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteAt(npyHeader(npyDescr(writer.dataType), writer.arrayShape(), writer.headerLength), 0)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// npyHeader returns the magic string, version, header length and header of a version 1.0 .npy file,
+// padded with spaces to headerLength bytes, or to a multiple of 64 bytes if headerLength is 0.
+func npyHeader(descr string, shape []int, headerLength int) []byte {
+	var shapeString string
+	if len(shape) == 1 {
+		shapeString = fmt.Sprintf("(%d,)", shape[0])
+	} else {
+		dimensions := make([]string, len(shape))
+		for i, dimension := range shape {
+			dimensions[i] = fmt.Sprintf("%d", dimension)
+		}
+		shapeString = "(" + strings.Join(dimensions, ", ") + ")"
+	}
+	dict := fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': %s, }", descr, shapeString)
+	if headerLength == 0 {
+		headerLength = (npyPrefixLength + len(dict) + 1 + npyHeaderAlignment - 1) / npyHeaderAlignment * npyHeaderAlignment
+	}
+	header := make([]byte, 0, headerLength)
+	header = append(header, npyMagic...)
+	header = append(header, 1, 0)
+	header = binary.LittleEndian.AppendUint16(header, uint16(headerLength-npyPrefixLength))
+	header = append(header, dict...)
+	for len(header) < headerLength-1 {
+		header = append(header, ' ')
+	}
+	return append(header, '\n')
+}
+
+// npyDescr returns the NumPy type string of the data type, in little-endian byte order.
+func npyDescr(dataType tdms.DataType) string {
+	switch dataType {
+	case tdms.DataTypeI8:
+		return "|i1"
+	case tdms.DataTypeI16:
+		return "<i2"
+	case tdms.DataTypeI32:
+		return "<i4"
+	case tdms.DataTypeI64:
+		return "<i8"
+	case tdms.DataTypeU8:
+		return "|u1"
+	case tdms.DataTypeU16:
+		return "<u2"
+	case tdms.DataTypeU32:
+		return "<u4"
+	case tdms.DataTypeU64:
+		return "<u8"
+	case tdms.DataTypeSingleFloat:
+		return "<f4"
+	default:
+		return "<f8"
+	}
+}
+
+func appendNPYValue(b []byte, dataType tdms.DataType, v float64) []byte {
+	switch dataType {
+	case tdms.DataTypeI8:
+		return append(b, byte(int8(v)))
+	case tdms.DataTypeI16:
+		return binary.LittleEndian.AppendUint16(b, uint16(int16(v)))
+	case tdms.DataTypeI32:
+		return binary.LittleEndian.AppendUint32(b, uint32(int32(v)))
+	case tdms.DataTypeI64:
+		return binary.LittleEndian.AppendUint64(b, uint64(int64(v)))
+	case tdms.DataTypeU8:
+		return append(b, uint8(v))
+	case tdms.DataTypeU16:
+		return binary.LittleEndian.AppendUint16(b, uint16(v))
+	case tdms.DataTypeU32:
+		return binary.LittleEndian.AppendUint32(b, uint32(v))
+	case tdms.DataTypeU64:
+		return binary.LittleEndian.AppendUint64(b, uint64(v))
+	case tdms.DataTypeSingleFloat:
+		return binary.LittleEndian.AppendUint32(b, math.Float32bits(float32(v)))
+	default:
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+	}
+}
@@ -0,0 +1,146 @@
+package converter
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ngyewch/tdms-go"
+	"github.com/ngyewch/tdms-go/internal/testfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNPYHeader(t *testing.T) {
+	{
+		header := npyHeader("<f8", []int{3}, 0)
+		dict := "{'descr': '<f8', 'fortran_order': False, 'shape': (3,), }"
+		// magic string, version 1.0, little-endian header length, dict padded with spaces and terminated by a newline to a multiple of 64 bytes
+		expected := "\x93NUMPY\x01\x00\x76\x00" + dict + strings.Repeat(" ", 128-10-len(dict)-1) + "\n"
+		assert.Equal(t, expected, string(header))
+	}
+	{
+		header := npyHeader("<i2", []int{5, 2}, 0)
+		assert.Equal(t, 0, len(header)%npyHeaderAlignment)
+		assert.Equal(t, uint16(len(header)-10), binary.LittleEndian.Uint16(header[8:10]))
+		assert.Contains(t, string(header), "'shape': (5, 2), }")
+		// the rewritten header keeps the reserved length
+		reserved := npyHeader("<i2", []int{math.MaxInt64, 2}, 0)
+		rewritten := npyHeader("<i2", []int{5, 2}, len(reserved))
+		assert.Len(t, rewritten, len(reserved))
+		assert.Equal(t, byte('\n'), rewritten[len(rewritten)-1])
+	}
+	{
+		descrs := map[tdms.DataType]string{
+			tdms.DataTypeI8:          "|i1",
+			tdms.DataTypeI16:         "<i2",
+			tdms.DataTypeI32:         "<i4",
+			tdms.DataTypeI64:         "<i8",
+			tdms.DataTypeU8:          "|u1",
+			tdms.DataTypeU16:         "<u2",
+			tdms.DataTypeU32:         "<u4",
+			tdms.DataTypeU64:         "<u8",
+			tdms.DataTypeSingleFloat: "<f4",
+			tdms.DataTypeDoubleFloat: "<f8",
+		}
+		for dataType, descr := range descrs {
+			assert.Equal(t, descr, npyDescr(dataType), dataType.String())
+		}
+	}
+}
+
+func TestConvertToNPY(t *testing.T) {
+	startTime := tdms.NewTimestamp(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	inputFile := writeTestFile(t,
+		testChannel{group: "g 1", name: "a", dataType: tdms.DataTypeI16, values: []int16{-1, 2, 3},
+			properties: testfile.WaveformProperties(testfile.Timestamp(startTime), 0.5)},
+		testChannel{group: "g 1", name: "b", dataType: tdms.DataTypeDoubleFloat, values: []float64{1.5}},
+	)
+	dir := t.TempDir()
+	err := ConvertToNPY(inputFile, filepath.Join(dir, "data.npy"), Options{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	{
+		b, err := os.ReadFile(filepath.Join(dir, "data_g-1_a.npy"))
+		if assert.NoError(t, err) {
+			headerLength := 10 + int(binary.LittleEndian.Uint16(b[8:10]))
+			assert.Equal(t, npyHeader("<i2", []int{3}, headerLength), b[:headerLength])
+			assert.Equal(t, []byte{0xff, 0xff, 2, 0, 3, 0}, b[headerLength:])
+		}
+	}
+	{
+		b, err := os.ReadFile(filepath.Join(dir, "data.json"))
+		if !assert.NoError(t, err) {
+			return
+		}
+		var sidecar npySidecar
+		if !assert.NoError(t, json.Unmarshal(b, &sidecar)) {
+			return
+		}
+		assert.Equal(t, []tdms.PropertyInfo{{Name: "name", Type: "String", Value: "test"}}, sidecar.Properties)
+		if assert.Len(t, sidecar.Groups, 1) && assert.Len(t, sidecar.Groups[0].Channels, 2) {
+			assert.Equal(t, uint64(3), sidecar.Groups[0].Channels[0].SampleCount)
+		}
+		assert.Equal(t, []npyArrayInfo{
+			{Path: "/'g 1'/'a'", Name: "data_g-1_a.npy", DType: "<i2", Shape: []int{3}, StartTime: &startTime, Increment: 0.5},
+			{Path: "/'g 1'/'b'", Name: "data_g-1_b.npy", DType: "<f8", Shape: []int{1}},
+		}, sidecar.Arrays)
+	}
+}
+
+func TestConvertToNPZ(t *testing.T) {
+	// more channels than files kept open
+	var channels []testChannel
+	for i := range npyMaxOpenFiles + 2 {
+		channels = append(channels, testChannel{group: "g", name: fmt.Sprintf("c%d", i), dataType: tdms.DataTypeU8, values: []uint8{uint8(i)}})
+	}
+	outputFile := filepath.Join(t.TempDir(), "data.npz")
+	err := ConvertToNPZ(writeTestFile(t, channels...), outputFile, Options{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	reader, err := zip.OpenReader(outputFile)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func(reader *zip.ReadCloser) {
+		_ = reader.Close()
+	}(reader)
+	if assert.Len(t, reader.File, len(channels)) {
+		last := reader.File[len(channels)-1]
+		assert.Equal(t, fmt.Sprintf("g/c%d.npy", len(channels)-1), last.Name)
+		r, err := last.Open()
+		if assert.NoError(t, err) {
+			b, err := io.ReadAll(r)
+			assert.NoError(t, err)
+			assert.Equal(t, npyHeader("|u1", []int{1}, len(b)-1), b[:len(b)-1])
+			assert.Equal(t, byte(len(channels)-1), b[len(b)-1])
+			_ = r.Close()
+		}
+	}
+}
+
+func TestNPYNameCollision(t *testing.T) {
+	inputFile := writeTestFile(t,
+		testChannel{group: "A b", name: "c", dataType: tdms.DataTypeDoubleFloat, values: []float64{1}},
+		testChannel{group: "a-b", name: "c", dataType: tdms.DataTypeDoubleFloat, values: []float64{2}},
+	)
+	err := ConvertToNPY(inputFile, filepath.Join(t.TempDir(), "data.npy"), Options{})
+	if assert.Error(t, err) {
+		assert.Equal(t, "channel /'a-b'/'c' has the same array name data_a-b_c.npy as another channel", err.Error())
+	}
+	inputFile = writeTestFile(t,
+		testChannel{group: "a/b", name: "c", dataType: tdms.DataTypeDoubleFloat, values: []float64{1}},
+		testChannel{group: "a", name: "b/c", dataType: tdms.DataTypeDoubleFloat, values: []float64{2}},
+	)
+	err = ConvertToNPZ(inputFile, filepath.Join(t.TempDir(), "data.npz"), Options{})
+	assert.Error(t, err)
+}
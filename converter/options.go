@@ -0,0 +1,300 @@
+package converter
+
+import (
+	"fmt"
+	"iter"
+	"math"
+
+	"github.com/ngyewch/tdms-go"
+)
+
+const (
+	calibrationAttributeName        = "tdms_calibration"
+	calibrationScalersAttributeName = "tdms_calibration_scalers"
+
+	timeSuffix             = "_time"
+	timeUnitsAttributeName = "units"
+	// timeColumnName is the name of the time column of the tabular formats.
+	timeColumnName = "time"
+)
+
+type Options struct {
+	Calibration *tdms.Calibration
+	// IncludeTime emits a time coordinate for each channel, in seconds relative to the channel's start time.
+	IncludeTime bool
+	// Resample resamples all channels onto a common time axis at ResampleRate. If ResampleRate is 0, the rate of the fastest channel is used.
+	Resample       bool
+	ResampleRate   float64
+	ResampleMethod tdms.ResampleMethod
+	// From and To select the samples within a time range. A nil bound is unbounded.
+	From *tdms.TimeBound
+	To   *tdms.TimeBound
+	// SortByName orders groups, channels and properties by name, instead of the order they are defined in the file.
+	SortByName bool
+	// Delimiter separates the columns of CSV/TSV output. Defaults to ',' for CSV and '\t' for TSV.
+	Delimiter rune
+	// FloatFormat is the fmt format of the sample values in CSV/TSV output, e.g. "%.6f", with exactly one float verb (%b, %e, %E, %f, %F,
+	// %g, %G, %x or %X). Defaults to the shortest representation that round-trips.
+	FloatFormat string
+	// UnitRow adds a header row with the units (unit_string) of the channels to CSV/TSV output.
+	UnitRow bool
+	// Include selects the channels matching any of the filters. If empty, all channels are selected.
+	Include []tdms.ChannelFilter
+	// Exclude deselects the channels matching any of the filters.
+	Exclude []tdms.ChannelFilter
+	// StartSample and EndSample select the samples of each channel with indexes StartSample <= i < EndSample. An EndSample of 0 is unbounded.
+	StartSample uint64
+	EndSample   uint64
+	// Decimation keeps only every Decimation-th sample of each channel. 0 and 1 keep all samples.
+	Decimation uint64
+	// Raw writes the values without scaling, i.e. without the scalers of the file or the calibration.
+	Raw bool
+	// ValueType is the type of all sample values, DataTypeSingleFloat or DataTypeDoubleFloat. If 0, formats with typed columns
+	// keep the native type of samples that are not scaled, and other formats write DataTypeDoubleFloat.
+	ValueType tdms.DataType
+}
+
+// readData reads the data of the file, resampled onto a common time axis if resampling is enabled.
+func (options Options) readData(file *tdms.File, chunkHandler func(chunk tdms.Chunk) error) error {
+	if !options.Resample {
+		return file.ReadData(chunkHandler, options.readOptions()...)
+	}
+	paths, err := options.resamplePaths(file)
+	if err != nil {
+		return err
+	}
+	target, err := file.ResampleTimeAxis(paths, options.ResampleRate, options.readOptions()...)
+	if err != nil {
+		return err
+	}
+	return file.ReadResampled(chunkHandler, paths, target, options.ResampleMethod, options.readOptions()...)
+}
+
+// resamplePaths returns the paths of the selected channels that are resampled, i.e. those with waveform attributes.
+func (options Options) resamplePaths(file *tdms.File) ([]string, error) {
+	rateClasses, err := file.RateClasses()
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, rateClass := range rateClasses {
+		for _, path := range rateClass.Paths {
+			if options.selected(file.Node(path)) {
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// channels returns the channels read by readData, in the chosen order, together with the number of samples of each channel.
+func (options Options) channels(file *tdms.File) ([]*tdms.Node, map[string]uint64, error) {
+	var channels []*tdms.Node
+	if options.Resample {
+		paths, err := options.resamplePaths(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		target, err := file.ResampleTimeAxis(paths, options.ResampleRate, options.readOptions()...)
+		if err != nil {
+			return nil, nil, err
+		}
+		sampleCounts := make(map[string]uint64)
+		for _, path := range paths {
+			channels = append(channels, file.Node(path))
+			sampleCounts[path] = target.Length
+		}
+		return options.orderChannels(file.Root(), channels), sampleCounts, nil
+	}
+	sampleCounts, err := file.GetChannelSampleCounts(options.readOptions()...)
+	if err != nil {
+		return nil, nil, err
+	}
+	for path, sampleCount := range sampleCounts {
+		if (sampleCount > 0) && options.selected(file.Node(path)) {
+			channels = append(channels, file.Node(path))
+		}
+	}
+	return options.orderChannels(file.Root(), channels), sampleCounts, nil
+}
+
+// selected reports whether the channel is selected by the Include and Exclude filters.
+func (options Options) selected(channel *tdms.Node) bool {
+	if channel == nil {
+		return false
+	}
+	for _, filter := range options.Exclude {
+		if filter(channel) {
+			return false
+		}
+	}
+	if len(options.Include) == 0 {
+		return true
+	}
+	for _, filter := range options.Include {
+		if filter(channel) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTimeColumn checks that a single time column applies to all channels, i.e. that the channels have the same start time and increment,
+// unless resampling.
+func (options Options) checkTimeColumn(channels []*tdms.Node) error {
+	if options.Resample {
+		return nil
+	}
+	var first *tdms.WaveformAttributes
+	for i, channel := range channels {
+		waveformAttributes, err := tdms.GetWaveformAttributes(channel.Properties().Collect())
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			first = waveformAttributes
+		} else if waveformAttributes.Increment != first.Increment {
+			return fmt.Errorf("channels have different sample rates, resample to include a time column")
+		} else if waveformAttributes.StartTime != first.StartTime {
+			return fmt.Errorf("channels have different start times, resample to include a time column")
+		}
+	}
+	return nil
+}
+
+// columnNames returns the column or field names of the channels: the channel name, or group/channel if the channel name is shared by another
+// channel or is the name of the time column. The names must be unique, e.g. the channel b/c of group a and the channel c of group a/b clash.
+func columnNames(channels []*tdms.Node, includeTime bool) ([]string, error) {
+	nameCounts := make(map[string]int)
+	if includeTime {
+		nameCounts[timeColumnName]++
+	}
+	for _, channel := range channels {
+		nameCounts[channel.Name()]++
+	}
+	fieldNames := make([]string, len(channels))
+	fieldPaths := make(map[string]string)
+	for i, channel := range channels {
+		fieldName := channel.Name()
+		if nameCounts[fieldName] > 1 {
+			fieldName = channel.Parent().Name() + "/" + fieldName
+		}
+		path, exists := fieldPaths[fieldName]
+		if exists {
+			return nil, fmt.Errorf("channels %s and %s have the same field name %s", path, channel.Path(), fieldName)
+		}
+		fieldPaths[fieldName] = channel.Path()
+		fieldNames[i] = fieldName
+	}
+	return fieldNames, nil
+}
+
+// valueDataType returns the type of the channel's sample values: ValueType if set, the data type of the channel's samples if they are not scaled,
+// and DataTypeDoubleFloat otherwise. The result is one of the integer types, DataTypeSingleFloat or DataTypeDoubleFloat.
+func (options Options) valueDataType(file *tdms.File, channel *tdms.Node) tdms.DataType {
+	if options.ValueType != 0 {
+		return options.ValueType
+	}
+	scaled := options.Resample
+	if !scaled && !options.Raw {
+		scalers, err := file.ChannelScalers(channel.Path())
+		scaled = (err != nil) || (len(scalers) > 0)
+		if !scaled && (options.Calibration != nil) {
+			scaled = len(options.Calibration.Scalers(channel.Path())) > 0
+		}
+	}
+	if scaled {
+		return tdms.DataTypeDoubleFloat
+	}
+	switch dataType := file.ChannelDataType(channel.Path()); dataType {
+	case tdms.DataTypeI8, tdms.DataTypeI16, tdms.DataTypeI32, tdms.DataTypeI64,
+		tdms.DataTypeU8, tdms.DataTypeU16, tdms.DataTypeU32, tdms.DataTypeU64,
+		tdms.DataTypeSingleFloat:
+		return dataType
+	case tdms.DataTypeSingleFloatWithUnit:
+		return tdms.DataTypeSingleFloat
+	default:
+		return tdms.DataTypeDoubleFloat
+	}
+}
+
+// singlePrecision reports whether the sample values are written as DataTypeSingleFloat by formats without typed columns.
+func (options Options) singlePrecision() bool {
+	return options.ValueType == tdms.DataTypeSingleFloat
+}
+
+// children returns the children of the node in the chosen order.
+func (options Options) children(node *tdms.Node) []*tdms.Node {
+	if options.SortByName {
+		return node.SortedChildren()
+	}
+	return node.Children()
+}
+
+// properties returns the properties of the node in the chosen order.
+func (options Options) properties(node *tdms.Node) iter.Seq2[string, any] {
+	if options.SortByName {
+		return node.Properties().Sorted()
+	}
+	return node.Properties().All()
+}
+
+// orderChannels returns the channels in the chosen order.
+func (options Options) orderChannels(root *tdms.Node, channels []*tdms.Node) []*tdms.Node {
+	channelSet := make(map[*tdms.Node]bool)
+	for _, channel := range channels {
+		channelSet[channel] = true
+	}
+	var orderedChannels []*tdms.Node
+	for _, group := range options.children(root) {
+		for _, channel := range options.children(group) {
+			if channelSet[channel] {
+				orderedChannels = append(orderedChannels, channel)
+			}
+		}
+	}
+	return orderedChannels
+}
+
+// datasetDimensions returns the dimensions of a channel's dataset: the number of samples, followed by the shape of each sample.
+func datasetDimensions(sampleCount int, shape []int) []int {
+	return append([]int{sampleCount}, shape...)
+}
+
+func (options Options) readOptions() []tdms.ReadOption {
+	var readOptions []tdms.ReadOption
+	if options.Calibration != nil {
+		readOptions = append(readOptions, tdms.WithCalibration(options.Calibration))
+	}
+	if (options.From != nil) || (options.To != nil) {
+		readOptions = append(readOptions, tdms.WithTimeRange(options.From, options.To))
+	}
+	if (options.StartSample > 0) || (options.EndSample > 0) {
+		end := options.EndSample
+		if end == 0 {
+			end = math.MaxUint64
+		}
+		readOptions = append(readOptions, tdms.WithSampleRange(options.StartSample, end))
+	}
+	if options.Decimation > 1 {
+		readOptions = append(readOptions, tdms.WithDecimation(options.Decimation))
+	}
+	if options.Raw {
+		readOptions = append(readOptions, tdms.WithRawValues())
+	}
+	return readOptions
+}
+
+// calibrationAttributes returns the attributes recording the calibration applied to the specified channel.
+func (options Options) calibrationAttributes(path string) map[string]string {
+	if (options.Calibration == nil) || options.Raw {
+		return nil
+	}
+	if len(options.Calibration.Scalers(path)) == 0 {
+		return nil
+	}
+	return map[string]string{
+		calibrationAttributeName:        options.Calibration.Label(),
+		calibrationScalersAttributeName: options.Calibration.Describe(path),
+	}
+}
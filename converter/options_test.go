@@ -0,0 +1,63 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/ngyewch/tdms-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatasetDimensions(t *testing.T) {
+	assert.Equal(t, []int{5}, datasetDimensions(5, nil))
+	assert.Equal(t, []int{0}, datasetDimensions(0, nil))
+	{
+		shape := make([]int, 2, 4)
+		shape[0], shape[1] = 3, 4
+		dimensions := datasetDimensions(5, shape)
+		assert.Equal(t, []int{5, 3, 4}, dimensions)
+		// the dimensions do not alias the sample shape
+		dimensions[1] = 7
+		assert.Equal(t, []int{3, 4}, shape)
+	}
+}
+
+func TestChannelSelection(t *testing.T) {
+	file, err := tdms.OpenFile(writeTestFile(t,
+		testChannel{group: "a", name: "x", dataType: tdms.DataTypeDoubleFloat, values: []float64{1}},
+		testChannel{group: "a", name: "y", dataType: tdms.DataTypeDoubleFloat, values: []float64{2}},
+		testChannel{group: "b", name: "x", dataType: tdms.DataTypeDoubleFloat, values: []float64{3}},
+		testChannel{group: "b", name: "z", dataType: tdms.DataTypeDoubleFloat, values: []float64{}},
+	))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func(file *tdms.File) {
+		_ = file.Close()
+	}(file)
+	glob := func(groupPattern string, channelPattern string) tdms.ChannelFilter {
+		filter, err := tdms.MatchGlob(groupPattern, channelPattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return filter
+	}
+	paths := func(options Options) []string {
+		source, err := NewSource(file, options)
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		var paths []string
+		for _, channel := range source.Channels {
+			paths = append(paths, channel.Path())
+		}
+		return paths
+	}
+	// channels without samples are not selected
+	assert.Equal(t, []string{"/'a'/'x'", "/'a'/'y'", "/'b'/'x'"}, paths(Options{}))
+	assert.Equal(t, []string{"/'a'/'x'", "/'a'/'y'"}, paths(Options{Include: []tdms.ChannelFilter{glob("a", "")}}))
+	assert.Equal(t, []string{"/'a'/'x'", "/'b'/'x'"}, paths(Options{Include: []tdms.ChannelFilter{glob("a", "x"), glob("b", "")}}))
+	// exclusion takes precedence over inclusion
+	assert.Equal(t, []string{"/'a'/'y'"}, paths(Options{Include: []tdms.ChannelFilter{glob("a", "")}, Exclude: []tdms.ChannelFilter{glob("", "x")}}))
+	assert.Equal(t, []string{"/'a'/'x'", "/'a'/'y'"}, paths(Options{Exclude: []tdms.ChannelFilter{glob("b", "")}}))
+	assert.Empty(t, paths(Options{Include: []tdms.ChannelFilter{glob("c", "")}}))
+}
@@ -0,0 +1,84 @@
+package converter
+
+import (
+	"os"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/ngyewch/tdms-go"
+)
+
+var parquetFormat = &format{
+	name:        "parquet",
+	description: "Apache Parquet, one column per channel",
+	extensions:  []string{".parquet"},
+	options:     []OptionInfo{timeOptionInfo},
+	newWriter: func(source *Source, outputFile string) (Writer, error) {
+		return newParquetWriter(source, outputFile)
+	},
+}
+
+func init() {
+	RegisterFormat(parquetFormat)
+}
+
+// ConvertToParquet writes one column per channel, and a time column if time is included, to a Parquet file.
+// Columns are named after the channels, qualified as group/channel if channels of different groups have the same name.
+// Samples that are not scaled keep their native type, except that I64 and U64 samples beyond ±2^53 are rounded, since samples are
+// read as float64 values. Each batch of chunks is written as a row group.
+// The properties of the root, the groups and the channels are stored in the file's key-value metadata, keyed by object path.
+func ConvertToParquet(inputFile string, outputFile string, options Options) error {
+	return Convert(inputFile, outputFile, parquetFormat, options)
+}
+
+// parquetWriter writes the record batches of an arrowWriter as row groups. Closing the Parquet writer also closes the output file.
+type parquetWriter struct {
+	writer        *arrowWriter
+	parquetWriter *pqarrow.FileWriter
+}
+
+func newParquetWriter(source *Source, outputFile string) (*parquetWriter, error) {
+	pw := &parquetWriter{}
+	writer, err := newArrowWriter(source, source.Channels, func(batch arrow.RecordBatch) error {
+		return pw.parquetWriter.Write(batch)
+	})
+	if err != nil {
+		return nil, err
+	}
+	pw.writer = writer
+
+	f, err := os.Create(outputFile)
+	if err != nil {
+		writer.release()
+		return nil, err
+	}
+
+	pw.parquetWriter, err = pqarrow.NewFileWriter(writer.schema, f,
+		parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy)),
+		pqarrow.NewArrowWriterProperties(pqarrow.WithStoreSchema()))
+	if err != nil {
+		writer.release()
+		_ = f.Close()
+		return nil, err
+	}
+	return pw, nil
+}
+
+func (pw *parquetWriter) WriteChunk(chunk tdms.Chunk) error {
+	return pw.writer.handleChunk(chunk)
+}
+
+func (pw *parquetWriter) Finish() error {
+	err := pw.writer.close()
+	if err != nil {
+		return err
+	}
+	return pw.parquetWriter.Close()
+}
+
+func (pw *parquetWriter) Close() error {
+	pw.writer.release()
+	return pw.parquetWriter.Close()
+}
@@ -0,0 +1,97 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/ngyewch/tdms-go"
+	"github.com/ngyewch/tdms-go/internal/testfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToParquet(t *testing.T) {
+	startTime := tdms.NewTimestamp(time.Date(2024, time.January, 1, 0, 0, 0, 123456789, time.UTC))
+	inputFile := writeTestFile(t,
+		testChannel{group: "g1", name: "a", dataType: tdms.DataTypeDoubleFloat, values: []float64{1.5, 2.5},
+			properties: append(testfile.WaveformProperties(testfile.Timestamp(startTime), 0.001), testfile.Property{Name: "unit_string", Value: "V"})},
+		testChannel{group: "g1", name: "n", dataType: tdms.DataTypeI32, values: []int32{-1, 2},
+			properties: testfile.WaveformProperties(testfile.Timestamp(startTime), 0.001)},
+		testChannel{group: "g2", name: "a", dataType: tdms.DataTypeI64, values: []int64{3, 4},
+			properties: testfile.WaveformProperties(testfile.Timestamp(startTime), 0.001)},
+	)
+	outputFile := filepath.Join(t.TempDir(), "output.parquet")
+	err := ConvertToParquet(inputFile, outputFile, Options{IncludeTime: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	reader, err := file.OpenParquetFile(outputFile, false)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func(reader *file.Reader) {
+		_ = reader.Close()
+	}(reader)
+	{
+		// the properties are stored in the key-value metadata, keyed by object path
+		value := reader.MetaData().KeyValueMetadata().FindValue("/")
+		if assert.NotNil(t, value) {
+			var propertyInfos []tdms.PropertyInfo
+			assert.NoError(t, json.Unmarshal([]byte(*value), &propertyInfos))
+			assert.Equal(t, []tdms.PropertyInfo{{Name: "name", Type: "String", Value: "test"}}, propertyInfos)
+		}
+		assert.NotNil(t, reader.MetaData().KeyValueMetadata().FindValue("/'g2'/'a'"))
+	}
+
+	fileReader, err := pqarrow.NewFileReader(reader, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if !assert.NoError(t, err) {
+		return
+	}
+	table, err := fileReader.ReadTable(context.Background())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer table.Release()
+	schema := table.Schema()
+	if !assert.Equal(t, 4, schema.NumFields()) {
+		return
+	}
+	// the channels named a are qualified with their groups
+	assert.Equal(t, "time", schema.Field(0).Name)
+	assert.Equal(t, &arrow.TimestampType{Unit: arrow.Nanosecond, TimeZone: "UTC"}, schema.Field(0).Type)
+	assert.Equal(t, "g1/a", schema.Field(1).Name)
+	assert.Equal(t, arrow.PrimitiveTypes.Float64, schema.Field(1).Type)
+	assert.Equal(t, "n", schema.Field(2).Name)
+	assert.Equal(t, arrow.PrimitiveTypes.Int32, schema.Field(2).Type)
+	assert.Equal(t, "g2/a", schema.Field(3).Name)
+	assert.Equal(t, arrow.PrimitiveTypes.Int64, schema.Field(3).Type)
+	unit, _ := schema.Field(1).Metadata.GetValue("unit_string")
+	assert.Equal(t, "V", unit)
+
+	times := table.Column(0).Data().Chunk(0).(*array.Timestamp)
+	assert.Equal(t, []arrow.Timestamp{1704067200123456789, 1704067200124456789}, times.Values())
+	assert.Equal(t, []float64{1.5, 2.5}, table.Column(1).Data().Chunk(0).(*array.Float64).Float64Values())
+	assert.Equal(t, []int32{-1, 2}, table.Column(2).Data().Chunk(0).(*array.Int32).Int32Values())
+	assert.Equal(t, []int64{3, 4}, table.Column(3).Data().Chunk(0).(*array.Int64).Int64Values())
+}
+
+func TestArrowFieldNames(t *testing.T) {
+	inputFile := writeTestFile(t,
+		testChannel{group: "a", name: "x", dataType: tdms.DataTypeDoubleFloat, values: []float64{1}},
+		testChannel{group: "b", name: "x", dataType: tdms.DataTypeDoubleFloat, values: []float64{2}},
+		testChannel{group: "c", name: "a/x", dataType: tdms.DataTypeDoubleFloat, values: []float64{3}},
+	)
+	outputFile := filepath.Join(t.TempDir(), "output.parquet")
+	err := ConvertToParquet(inputFile, outputFile, Options{})
+	if assert.Error(t, err) {
+		assert.Equal(t, "channels /'a'/'x' and /'c'/'a/x' have the same field name a/x", err.Error())
+	}
+}
@@ -0,0 +1,32 @@
+package converter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ngyewch/tdms-go"
+)
+
+// convertPropertyValue converts a property value to a value supported as an attribute by the output formats.
+func convertPropertyValue(propertyValue any) any {
+	switch v := propertyValue.(type) {
+	case tdms.Timestamp:
+		return v.String()
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case tdms.FixedPoint:
+		return v.Float64()
+	default:
+		return propertyValue
+	}
+}
+
+// formatPropertyValue converts a property value to a string.
+func formatPropertyValue(propertyValue any) string {
+	switch v := convertPropertyValue(propertyValue).(type) {
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
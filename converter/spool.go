@@ -0,0 +1,246 @@
+package converter
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+
+	"github.com/ngyewch/tdms-go"
+)
+
+// spoolBatchSize is the maximum number of values read back from a spool file at a time.
+const spoolBatchSize = 64 * 1024
+
+// spoolFile stores float64 values in a temporary file, in little-endian layout. The values of several sequences are appended to the same file,
+// so that spooling takes a single file descriptor however many channels are spooled.
+type spoolFile struct {
+	f    *os.File
+	w    *bufio.Writer
+	size int64
+}
+
+func newSpoolFile() (*spoolFile, error) {
+	f, err := os.CreateTemp("", "tdms-spool-*")
+	if err != nil {
+		return nil, err
+	}
+	return &spoolFile{
+		f: f,
+		w: bufio.NewWriter(f),
+	}, nil
+}
+
+func (spool *spoolFile) close() error {
+	err := spool.f.Close()
+	if err != nil {
+		return err
+	}
+	return os.Remove(spool.f.Name())
+}
+
+// spoolExtent is a range of bytes of a spool file.
+type spoolExtent struct {
+	offset int64
+	size   int64
+}
+
+// spoolSequence is a sequence of values stored in the extents of a spool file.
+type spoolSequence struct {
+	spool   *spoolFile
+	extents []spoolExtent
+	length  int
+}
+
+func (sequence *spoolSequence) write(values []float64) error {
+	spool := sequence.spool
+	offset := spool.size
+	var b [8]byte
+	for _, v := range values {
+		binary.LittleEndian.PutUint64(b[:], math.Float64bits(v))
+		_, err := spool.w.Write(b[:])
+		if err != nil {
+			return err
+		}
+	}
+	size := int64(8 * len(values))
+	spool.size += size
+	sequence.length += len(values)
+	if n := len(sequence.extents); (n > 0) && (sequence.extents[n-1].offset+sequence.extents[n-1].size == offset) {
+		sequence.extents[n-1].size += size
+	} else {
+		sequence.extents = append(sequence.extents, spoolExtent{offset: offset, size: size})
+	}
+	return nil
+}
+
+// each calls the handler with successive batches of at most spoolBatchSize values, holding whole samples of sampleSize values.
+// The batch is reused between calls.
+func (sequence *spoolSequence) each(sampleSize int, handler func(values []float64) error) error {
+	return eachSpooled(sequence, sampleSize, handler)
+}
+
+// eachFloat32 is like each, with the values converted to float32 as they are read.
+func (sequence *spoolSequence) eachFloat32(sampleSize int, handler func(values []float32) error) error {
+	return eachSpooled(sequence, sampleSize, handler)
+}
+
+// collect returns all the values.
+func (sequence *spoolSequence) collect() ([]float64, error) {
+	return collectSpooled[float64](sequence)
+}
+
+// collectFloat32 returns all the values, converted to float32 as they are read.
+func (sequence *spoolSequence) collectFloat32() ([]float32, error) {
+	return collectSpooled[float32](sequence)
+}
+
+func eachSpooled[T float32 | float64](sequence *spoolSequence, sampleSize int, handler func(values []T) error) error {
+	err := sequence.spool.w.Flush()
+	if err != nil {
+		return err
+	}
+	readers := make([]io.Reader, len(sequence.extents))
+	for i, extent := range sequence.extents {
+		readers[i] = io.NewSectionReader(sequence.spool.f, extent.offset, extent.size)
+	}
+	r := bufio.NewReader(io.MultiReader(readers...))
+	batchSize := max(1, spoolBatchSize/sampleSize) * sampleSize
+	b := make([]byte, 8*batchSize)
+	values := make([]T, batchSize)
+	for remaining := sequence.length; remaining > 0; {
+		n := min(remaining, batchSize)
+		_, err = io.ReadFull(r, b[:8*n])
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			values[i] = T(math.Float64frombits(binary.LittleEndian.Uint64(b[8*i:])))
+		}
+		err = handler(values[:n])
+		if err != nil {
+			return err
+		}
+		remaining -= n
+	}
+	return nil
+}
+
+func collectSpooled[T float32 | float64](sequence *spoolSequence) ([]T, error) {
+	values := make([]T, 0, sequence.length)
+	err := eachSpooled(sequence, 1, func(batch []T) error {
+		values = append(values, batch...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// channelSpool stores the samples of a channel, and their times if time is included, while the file is read.
+type channelSpool struct {
+	node  *tdms.Node
+	shape []int
+	// values holds the values of all samples, in row-major order.
+	values *spoolSequence
+	// times holds the relative times of the samples, or is nil if time is not included.
+	times     *spoolSequence
+	timeUnits string
+}
+
+func (spool *channelSpool) write(channel tdms.ChannelData) error {
+	if spool.values.length == 0 {
+		spool.shape = channel.Shape
+	}
+	err := spool.values.write(channel.Samples)
+	if err != nil {
+		return err
+	}
+	if spool.times != nil {
+		timeAxis := channel.TimeAxis()
+		if spool.times.length == 0 {
+			spool.timeUnits = timeAxis.Units()
+		}
+		return spool.times.write(timeAxis.CollectRelativeTimes())
+	}
+	return nil
+}
+
+// sampleSize returns the number of values of each sample.
+func (spool *channelSpool) sampleSize() int {
+	sampleSize := 1
+	for _, dimension := range spool.shape {
+		sampleSize *= dimension
+	}
+	return sampleSize
+}
+
+// dimensions returns the dimensions of the channel's dataset, see datasetDimensions.
+func (spool *channelSpool) dimensions() []int {
+	return datasetDimensions(spool.values.length/spool.sampleSize(), spool.shape)
+}
+
+// spoolWriter spools the samples of the source's channels to a single temporary file while the file is read, for formats which write
+// one dataset after another, or each dataset in one piece. The output is written from the spool once every chunk has been written,
+// so that only the samples of the dataset being written have to be held in memory, rather than those of every channel.
+type spoolWriter struct {
+	spool    *spoolFile
+	spools   []*channelSpool
+	spoolMap map[string]*channelSpool
+	finish   func(spools []*channelSpool) error
+}
+
+func newSpoolWriter(source *Source, finish func(spools []*channelSpool) error) (*spoolWriter, error) {
+	spool, err := newSpoolFile()
+	if err != nil {
+		return nil, err
+	}
+	writer := &spoolWriter{
+		spool:    spool,
+		spoolMap: make(map[string]*channelSpool),
+		finish:   finish,
+	}
+	for _, channel := range source.Channels {
+		channelSpool := &channelSpool{
+			node:   channel,
+			values: &spoolSequence{spool: spool},
+		}
+		if source.Options.IncludeTime {
+			channelSpool.times = &spoolSequence{spool: spool}
+		}
+		writer.spools = append(writer.spools, channelSpool)
+		writer.spoolMap[channel.Path()] = channelSpool
+	}
+	return writer, nil
+}
+
+func (writer *spoolWriter) WriteChunk(chunk tdms.Chunk) error {
+	for _, channel := range chunk.Channels {
+		spool, exists := writer.spoolMap[channel.Path]
+		if !exists {
+			continue
+		}
+		err := spool.write(channel)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (writer *spoolWriter) Finish() error {
+	return writer.finish(writer.spools)
+}
+
+// Close closes and removes the spool file.
+func (writer *spoolWriter) Close() error {
+	if writer.spool == nil {
+		return nil
+	}
+	err := writer.spool.close()
+	writer.spool = nil
+	writer.spools = nil
+	return err
+}
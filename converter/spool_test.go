@@ -0,0 +1,46 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpoolSequence(t *testing.T) {
+	spool, err := newSpoolFile()
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func(spool *spoolFile) {
+		_ = spool.close()
+	}(spool)
+	a := &spoolSequence{spool: spool}
+	b := &spoolSequence{spool: spool}
+	assert.NoError(t, a.write([]float64{1, 2}))
+	assert.NoError(t, a.write([]float64{3, 4}))
+	assert.NoError(t, b.write([]float64{-1}))
+	assert.NoError(t, a.write([]float64{5, 6}))
+	// consecutive writes of a sequence are merged into one extent
+	assert.Equal(t, []spoolExtent{{offset: 0, size: 32}, {offset: 40, size: 16}}, a.extents)
+
+	values, err := a.collect()
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float64{1, 2, 3, 4, 5, 6}, values)
+	}
+	values, err = b.collect()
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float64{-1}, values)
+	}
+	float32Values, err := a.collectFloat32()
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float32{1, 2, 3, 4, 5, 6}, float32Values)
+	}
+	var batchLengths []int
+	err = a.each(4, func(values []float64) error {
+		batchLengths = append(batchLengths, len(values))
+		return nil
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []int{6}, batchLengths)
+	}
+}
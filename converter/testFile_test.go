@@ -0,0 +1,43 @@
+package converter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ngyewch/tdms-go"
+	"github.com/ngyewch/tdms-go/internal/testfile"
+)
+
+// testChannel describes a channel of a test file, with its raw data values of the specified data type, e.g. []float64 for DataTypeDoubleFloat.
+type testChannel struct {
+	group      string
+	name       string
+	dataType   tdms.DataType
+	values     any
+	properties []testfile.Property
+}
+
+// writeTestFile writes a TDMS file with a single little-endian segment holding the channels, with the root property name "test",
+// to a temporary directory, and returns its path. The groups are defined in the order of their first channel.
+func writeTestFile(t *testing.T, channels ...testChannel) string {
+	segment := testfile.Segment{
+		ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData,
+		Objects: []testfile.Object{
+			{Path: "/", Properties: []testfile.Property{{Name: "name", Value: "test"}}},
+		},
+	}
+	groups := make(map[string]bool)
+	for _, channel := range channels {
+		if !groups[channel.group] {
+			groups[channel.group] = true
+			segment.Objects = append(segment.Objects, testfile.Object{Path: tdms.ObjectPath{Group: channel.group}.String()})
+		}
+		segment.Objects = append(segment.Objects, testfile.Object{
+			Path:         tdms.ObjectPath{Group: channel.group, Channel: channel.name}.String(),
+			RawDataIndex: testfile.RawDataIndex(channel.dataType, 1, uint64(reflect.ValueOf(channel.values).Len())),
+			Properties:   channel.properties,
+		})
+		segment.RawData = append(segment.RawData, testfile.RawData(channel.values)...)
+	}
+	return testfile.Write(t, segment)
+}
@@ -0,0 +1,333 @@
+package converter
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ngyewch/tdms-go"
+)
+
+const (
+	zarrVersion = 2
+	// zarrChunkLength is the number of samples of each chunk.
+	zarrChunkLength      = 64 * 1024
+	zarrCompressionLevel = 6
+
+	zarrGroupFileName      = ".zgroup"
+	zarrArrayFileName      = ".zarray"
+	zarrAttributesFileName = ".zattrs"
+
+	zarrPathAttributeName          = "tdms_path"
+	zarrPropertyTypesAttributeName = "tdms_property_types"
+	zarrWaveformAttributeName      = "tdms_waveform"
+)
+
+type zarrGroupMetadata struct {
+	ZarrFormat int `json:"zarr_format"`
+}
+
+type zarrArrayMetadata struct {
+	ZarrFormat int                 `json:"zarr_format"`
+	Shape      []int               `json:"shape"`
+	Chunks     []int               `json:"chunks"`
+	DType      string              `json:"dtype"`
+	Compressor zarrCompressor      `json:"compressor"`
+	FillValue  any                 `json:"fill_value"`
+	Order      string              `json:"order"`
+	Filters    []map[string]string `json:"filters"`
+}
+
+type zarrCompressor struct {
+	ID    string `json:"id"`
+	Level int    `json:"level"`
+}
+
+// zarrWaveform describes the time of the samples of an array.
+type zarrWaveform struct {
+	// FirstSample is the index of the first sample of the array within the channel.
+	FirstSample uint64          `json:"firstSample"`
+	StartTime   *tdms.Timestamp `json:"startTime,omitempty"`
+	Increment   float64         `json:"increment,omitempty"`
+	SampleRate  float64         `json:"sampleRate,omitempty"`
+}
+
+var zarrFormat = &format{
+	name:        "zarr",
+	description: "Zarr v2 directory store, one array per channel",
+	extensions:  []string{".zarr"},
+	newWriter: func(source *Source, outputFile string) (Writer, error) {
+		return newZarrWriter(source, outputFile)
+	},
+}
+
+func init() {
+	RegisterFormat(zarrFormat)
+}
+
+// ConvertToZarr writes the file to a Zarr v2 directory store. Each group becomes a Zarr group and each channel a zlib compressed, chunked array,
+// keyed by name, see zarrKeys. Groups, or channels of a group, whose keys clash are rejected.
+// Samples that are not scaled keep their native type, except that I64 and U64 samples beyond ±2^53 are rounded, since samples are read as
+// float64 values. The .zattrs of the root, the groups and the arrays hold the TDMS properties,
+// and the .zattrs of the arrays also hold the time of the samples. Chunks are written as soon as they are full, so that the samples never have to fit in memory.
+func ConvertToZarr(inputFile string, outputDir string, options Options) error {
+	return Convert(inputFile, outputDir, zarrFormat, options)
+}
+
+// zarrWriter writes the groups of the store up front, and each channel with a zarrArrayWriter.
+type zarrWriter struct {
+	writers   []*zarrArrayWriter
+	writerMap map[string]*zarrArrayWriter
+}
+
+func newZarrWriter(source *Source, outputDir string) (*zarrWriter, error) {
+	options := source.Options
+	groups := options.children(source.File.Root())
+	groupKeys, err := zarrKeys(groups)
+	if err != nil {
+		return nil, err
+	}
+	channelKeys, err := zarrKeys(source.Channels)
+	if err != nil {
+		return nil, err
+	}
+	err = writeZarrGroup(outputDir, source.File.Root(), options)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		err = writeZarrGroup(filepath.Join(outputDir, groupKeys[group]), group, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	writer := &zarrWriter{
+		writerMap: make(map[string]*zarrArrayWriter),
+	}
+	for _, channel := range source.Channels {
+		dir := filepath.Join(outputDir, groupKeys[channel.Parent()], channelKeys[channel])
+		err = os.MkdirAll(dir, 0755)
+		if err != nil {
+			return nil, err
+		}
+		arrayWriter := newZarrArrayWriter(dir, source.File, channel, options)
+		writer.writerMap[channel.Path()] = arrayWriter
+		writer.writers = append(writer.writers, arrayWriter)
+	}
+	return writer, nil
+}
+
+func (writer *zarrWriter) WriteChunk(chunk tdms.Chunk) error {
+	for _, channel := range chunk.Channels {
+		arrayWriter, exists := writer.writerMap[channel.Path]
+		if !exists {
+			continue
+		}
+		err := arrayWriter.write(channel)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (writer *zarrWriter) Finish() error {
+	for _, arrayWriter := range writer.writers {
+		err := arrayWriter.close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close does nothing, since the chunks are written to files as soon as they are full.
+func (writer *zarrWriter) Close() error {
+	return nil
+}
+
+// zarrKeys returns the key of each node within its parent: the name, with "/" and "\\" replaced by "_", and with a leading "." replaced by "_"
+// so that the key is neither a path nor a metadata file. Nodes of the same parent whose keys only differ in case are rejected,
+// since they are the same directory on case-insensitive file systems.
+func zarrKeys(nodes []*tdms.Node) (map[*tdms.Node]string, error) {
+	keys := make(map[*tdms.Node]string)
+	keyNodes := make(map[string]*tdms.Node)
+	for _, node := range nodes {
+		key := strings.NewReplacer("/", "_", "\\", "_").Replace(node.Name())
+		if (key == "") || strings.HasPrefix(key, ".") {
+			key = "_" + strings.TrimPrefix(key, ".")
+		}
+		parentKey := node.Parent().Path() + "/" + strings.ToLower(key)
+		other, exists := keyNodes[parentKey]
+		if exists {
+			return nil, fmt.Errorf("%s and %s have the same Zarr key %s", other.Path(), node.Path(), key)
+		}
+		keyNodes[parentKey] = node
+		keys[node] = key
+	}
+	return keys, nil
+}
+
+func writeZarrGroup(dir string, node *tdms.Node, options Options) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	err = writeZarrJSON(filepath.Join(dir, zarrGroupFileName), zarrGroupMetadata{
+		ZarrFormat: zarrVersion,
+	})
+	if err != nil {
+		return err
+	}
+	return writeZarrJSON(filepath.Join(dir, zarrAttributesFileName), zarrAttributes(node, options))
+}
+
+// zarrAttributes returns the properties of the node, together with their TDMS data types.
+func zarrAttributes(node *tdms.Node, options Options) map[string]any {
+	attributes := map[string]any{
+		zarrPathAttributeName: node.Path(),
+	}
+	propertyTypes := make(map[string]string)
+	for name := range options.properties(node) {
+		propertyInfo, _ := node.PropertyInfo(name)
+		attributes[name] = propertyInfo.Value
+		propertyTypes[name] = propertyInfo.Type
+	}
+	attributes[zarrPropertyTypesAttributeName] = propertyTypes
+	return attributes
+}
+
+func writeZarrJSON(path string, v any) error {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b.Bytes(), 0644)
+}
+
+// zarrArrayWriter writes the samples of a channel to the chunks of a Zarr array, in little-endian layout.
+type zarrArrayWriter struct {
+	dir         string
+	node        *tdms.Node
+	options     Options
+	dataType    tdms.DataType
+	sampleShape []int
+	sampleSize  int
+	// buffer holds the encoded samples of the current chunk.
+	buffer      []byte
+	chunkIndex  int
+	sampleCount int
+	waveform    zarrWaveform
+}
+
+func newZarrArrayWriter(dir string, tdmsFile *tdms.File, channel *tdms.Node, options Options) *zarrArrayWriter {
+	writer := &zarrArrayWriter{
+		dir:        dir,
+		node:       channel,
+		options:    options,
+		dataType:   options.valueDataType(tdmsFile, channel),
+		sampleSize: 1,
+	}
+	arrayDimension := tdmsFile.ChannelArrayDimension(channel.Path())
+	if arrayDimension > 1 {
+		writer.sampleShape = []int{int(arrayDimension)}
+		writer.sampleSize = int(arrayDimension)
+	}
+	return writer
+}
+
+func (writer *zarrArrayWriter) chunkByteSize() int {
+	return zarrChunkLength * writer.sampleSize * writer.dataType.SizeInBytes()
+}
+
+func (writer *zarrArrayWriter) write(channel tdms.ChannelData) error {
+	if writer.sampleCount == 0 {
+		writer.waveform.FirstSample = channel.SampleOffset
+		if (channel.WaveformAttributes != nil) && (channel.WaveformAttributes.Increment > 0) {
+			timeAxis := channel.TimeAxis()
+			startTime := timeAxis.AbsoluteTime(0)
+			writer.waveform.StartTime = &startTime
+			writer.waveform.Increment = timeAxis.Increment
+			writer.waveform.SampleRate = channel.WaveformAttributes.SampleRate()
+		}
+	}
+	for _, v := range channel.Samples {
+		writer.buffer = appendNPYValue(writer.buffer, writer.dataType, v)
+		if len(writer.buffer) == writer.chunkByteSize() {
+			err := writer.writeChunk()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	writer.sampleCount += channel.SampleCount()
+	return nil
+}
+
+// writeChunk writes the current chunk, padded with the fill value (0).
+func (writer *zarrArrayWriter) writeChunk() error {
+	for len(writer.buffer) < writer.chunkByteSize() {
+		writer.buffer = appendNPYValue(writer.buffer, writer.dataType, 0)
+	}
+	key := fmt.Sprintf("%d", writer.chunkIndex)
+	for range writer.sampleShape {
+		key += ".0"
+	}
+	var compressed bytes.Buffer
+	w, err := zlib.NewWriterLevel(&compressed, zarrCompressionLevel)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(writer.buffer)
+	if err != nil {
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(filepath.Join(writer.dir, key), compressed.Bytes(), 0644)
+	if err != nil {
+		return err
+	}
+	writer.buffer = writer.buffer[:0]
+	writer.chunkIndex++
+	return nil
+}
+
+// close writes the last chunk, and the metadata and attributes of the array.
+func (writer *zarrArrayWriter) close() error {
+	if len(writer.buffer) > 0 {
+		err := writer.writeChunk()
+		if err != nil {
+			return err
+		}
+	}
+	err := writeZarrJSON(filepath.Join(writer.dir, zarrArrayFileName), zarrArrayMetadata{
+		ZarrFormat: zarrVersion,
+		Shape:      append([]int{writer.sampleCount}, writer.sampleShape...),
+		Chunks:     append([]int{zarrChunkLength}, writer.sampleShape...),
+		DType:      npyDescr(writer.dataType),
+		Compressor: zarrCompressor{
+			ID:    "zlib",
+			Level: zarrCompressionLevel,
+		},
+		FillValue: 0,
+		Order:     "C",
+	})
+	if err != nil {
+		return err
+	}
+	attributes := zarrAttributes(writer.node, writer.options)
+	attributes[zarrWaveformAttributeName] = writer.waveform
+	return writeZarrJSON(filepath.Join(writer.dir, zarrAttributesFileName), attributes)
+}
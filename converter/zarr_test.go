@@ -0,0 +1,98 @@
+package converter
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ngyewch/tdms-go"
+	"github.com/ngyewch/tdms-go/internal/testfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToZarr(t *testing.T) {
+	startTime := tdms.NewTimestamp(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	inputFile := writeTestFile(t,
+		testChannel{group: "g/1", name: ".a", dataType: tdms.DataTypeI16, values: []int16{-1, 2, 3},
+			properties: append(testfile.WaveformProperties(testfile.Timestamp(startTime), 0.5), testfile.Property{Name: "gain", Value: int32(2)})},
+	)
+	outputDir := filepath.Join(t.TempDir(), "data.zarr")
+	err := ConvertToZarr(inputFile, outputDir, Options{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	readJSON := func(path string) map[string]any {
+		b, err := os.ReadFile(filepath.Join(outputDir, path))
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		var v map[string]any
+		assert.NoError(t, json.Unmarshal(b, &v))
+		return v
+	}
+	assert.Equal(t, map[string]any{"zarr_format": float64(2)}, readJSON(".zgroup"))
+	assert.Equal(t, map[string]any{"zarr_format": float64(2)}, readJSON("g_1/.zgroup"))
+	assert.Equal(t, "/'g/1'", readJSON("g_1/.zattrs")["tdms_path"])
+	assert.Equal(t, map[string]any{
+		"zarr_format": float64(2),
+		"shape":       []any{float64(3)},
+		"chunks":      []any{float64(zarrChunkLength)},
+		"dtype":       "<i2",
+		"compressor":  map[string]any{"id": "zlib", "level": float64(zarrCompressionLevel)},
+		"fill_value":  float64(0),
+		"order":       "C",
+		"filters":     nil,
+	}, readJSON("g_1/_a/.zarray"))
+	{
+		attributes := readJSON("g_1/_a/.zattrs")
+		assert.Equal(t, "/'g/1'/'.a'", attributes["tdms_path"])
+		assert.Equal(t, float64(2), attributes["gain"])
+		assert.Equal(t, "I32", attributes["tdms_property_types"].(map[string]any)["gain"])
+		assert.Equal(t, "TimeStamp", attributes["tdms_property_types"].(map[string]any)["wf_start_time"])
+		assert.Equal(t, map[string]any{"firstSample": float64(0), "startTime": startTime.String(), "increment": 0.5, "sampleRate": float64(2)}, attributes["tdms_waveform"])
+	}
+	{
+		// the chunk is padded with the fill value
+		b, err := os.ReadFile(filepath.Join(outputDir, "g_1/_a/0"))
+		if !assert.NoError(t, err) {
+			return
+		}
+		r, err := zlib.NewReader(bytes.NewReader(b))
+		if !assert.NoError(t, err) {
+			return
+		}
+		chunk, err := io.ReadAll(r)
+		if assert.NoError(t, err) && assert.Len(t, chunk, zarrChunkLength*2) {
+			assert.Equal(t, []byte{0xff, 0xff, 2, 0, 3, 0}, chunk[:6])
+			assert.Equal(t, make([]byte, len(chunk)-6), chunk[6:])
+		}
+	}
+}
+
+func TestZarrKeys(t *testing.T) {
+	{
+		inputFile := writeTestFile(t,
+			testChannel{group: "a/b", name: "c", dataType: tdms.DataTypeDoubleFloat, values: []float64{1}},
+			testChannel{group: "a_b", name: "c", dataType: tdms.DataTypeDoubleFloat, values: []float64{2}},
+		)
+		err := ConvertToZarr(inputFile, filepath.Join(t.TempDir(), "data.zarr"), Options{})
+		if assert.Error(t, err) {
+			assert.Equal(t, "/'a/b' and /'a_b' have the same Zarr key a_b", err.Error())
+		}
+	}
+	{
+		inputFile := writeTestFile(t,
+			testChannel{group: "g", name: "X", dataType: tdms.DataTypeDoubleFloat, values: []float64{1}},
+			testChannel{group: "g", name: "x", dataType: tdms.DataTypeDoubleFloat, values: []float64{2}},
+		)
+		err := ConvertToZarr(inputFile, filepath.Join(t.TempDir(), "data.zarr"), Options{})
+		if assert.Error(t, err) {
+			assert.Equal(t, "/'g'/'X' and /'g'/'x' have the same Zarr key x", err.Error())
+		}
+	}
+}
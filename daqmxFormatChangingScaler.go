@@ -44,6 +44,35 @@ func (scaler *DAQmxFormatChangingScaler) ScaleId() uint32 {
 	return scaler.scaleId
 }
 
+func (scaler *DAQmxFormatChangingScaler) DataType() DataType {
+	return scaler.dataType
+}
+
+func (scaler *DAQmxFormatChangingScaler) RawBufferIndex() uint32 {
+	return scaler.rawBufferIndex
+}
+
+func (scaler *DAQmxFormatChangingScaler) RawByteOffsetWithinTheStride() uint32 {
+	return scaler.rawByteOffsetWithinTheStride
+}
+
+func (scaler *DAQmxFormatChangingScaler) SampleFormatBitmap() uint32 {
+	return scaler.sampleFormatBitmap
+}
+
+func (scaler *DAQmxFormatChangingScaler) Info() ScalerInfo {
+	return ScalerInfo{
+		Type:    "DAQmxFormatChanging",
+		ScaleId: scaler.scaleId,
+		Parameters: map[string]any{
+			"DataType":                     scaler.dataType.String(),
+			"RawBufferIndex":               scaler.rawBufferIndex,
+			"RawByteOffsetWithinTheStride": scaler.rawByteOffsetWithinTheStride,
+			"SampleFormatBitmap":           scaler.sampleFormatBitmap,
+		},
+	}
+}
+
 func (scaler *DAQmxFormatChangingScaler) Scale(v any) (float64, error) {
 	return 0, fmt.Errorf("DAQmxFormatChangingScaler.Scale not implemented")
 }
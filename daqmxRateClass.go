@@ -0,0 +1,255 @@
+package tdms
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/ngyewch/tdms-go/utils"
+	"github.com/samber/oops"
+)
+
+// RateClass is a group of channels acquired at the same sample rate.
+type RateClass struct {
+	Increment float64
+	Paths     []string
+}
+
+func (rateClass RateClass) SampleRate() float64 {
+	return 1 / rateClass.Increment
+}
+
+type daqmxChannel struct {
+	object             *Object
+	node               *Node
+	rawDataIndex       *DAQmxRawDataIndex
+	waveformAttributes *WaveformAttributes
+	scalers            []Scaler
+}
+
+// daqmxRateClass is a group of channels of a DAQmx segment sharing the same raw data layout and increment.
+//
+// Segments follow the DAQmx raw data layout read by npTDMS: each chunk holds ChunkSize samples, each consisting of one record of
+// each raw buffer. The last chunk of a segment may be incomplete, in which case it holds as many whole samples as fit. Neither NI's
+// TDMS format description nor npTDMS describe segments whose channels have different increments, and no sample file with such
+// segments is available, so segments with more than one rate class are rejected rather than read using a guessed layout.
+type daqmxRateClass struct {
+	increment      float64
+	chunkSize      uint64
+	arrayDimension uint32
+	buffers        [][]byte
+	channels       []daqmxChannel
+	sampleCount    uint64
+}
+
+// sampleByteSize returns the number of bytes of each sample, which consists of one record of the raw buffers for each array element.
+func (rateClass *daqmxRateClass) sampleByteSize() uint64 {
+	var totalRawDataWidth uint64
+	for _, buffer := range rateClass.buffers {
+		totalRawDataWidth += uint64(len(buffer))
+	}
+	return totalRawDataWidth * uint64(rateClass.arrayDimension)
+}
+
+func (rateClass *daqmxRateClass) accepts(channel daqmxChannel) bool {
+	if rateClass.increment != channel.waveformAttributes.Increment {
+		return false
+	}
+	return rateClass.channels[0].rawDataIndex.CheckCompatibility(channel.rawDataIndex) == nil
+}
+
+// getDAQmxRateClasses groups the channels of the DAQmx segment into rate classes, and computes the number of samples of each rate class.
+func (file *File) getDAQmxRateClasses(segment *Segment, readOptions *readOptions) ([]*daqmxRateClass, error) {
+	var rateClasses []*daqmxRateClass
+	for _, object := range segment.MetaData.Objects() {
+		if object.RawDataIndex == nil {
+			continue
+		}
+		daqmxRawDataIndex, ok := object.RawDataIndex.(*DAQmxRawDataIndex)
+		if !ok {
+			return nil, fmt.Errorf("DAQmx raw data index expected")
+		}
+		if len(daqmxRawDataIndex.Scalers) <= 0 {
+			return nil, fmt.Errorf("no scalers defined")
+		}
+		_, ok = daqmxRawDataIndex.Scalers[0].(*DAQmxFormatChangingScaler)
+		if !ok {
+			return nil, fmt.Errorf("DAQmx format changing scaler expected as first scaler")
+		}
+		node := file.Node(object.Path)
+		if node == nil {
+			return nil, fmt.Errorf("could not find object node")
+		}
+		// the properties as of the segment, rather than as of the last segment
+		timed := newTimedChannel(node)
+		timed.replay(segment.Offset)
+		waveformAttributes, err := GetWaveformAttributes(timed.properties)
+		if err != nil {
+			return nil, err
+		}
+		channel := daqmxChannel{
+			object:             object,
+			node:               node,
+			rawDataIndex:       daqmxRawDataIndex,
+			waveformAttributes: waveformAttributes,
+			scalers:            readOptions.getScalers(object.Path, daqmxRawDataIndex.Scalers),
+		}
+		index := slices.IndexFunc(rateClasses, func(rateClass *daqmxRateClass) bool {
+			return rateClass.accepts(channel)
+		})
+		if index < 0 {
+			buffers := make([][]byte, len(daqmxRawDataIndex.RawDataWidths))
+			for i, rawDataWidth := range daqmxRawDataIndex.RawDataWidths {
+				buffers[i] = make([]byte, rawDataWidth)
+			}
+			rateClasses = append(rateClasses, &daqmxRateClass{
+				increment:      waveformAttributes.Increment,
+				chunkSize:      daqmxRawDataIndex.ChunkSize,
+				arrayDimension: daqmxRawDataIndex.ArrayDimension,
+				buffers:        buffers,
+			})
+			index = len(rateClasses) - 1
+		}
+		rateClasses[index].channels = append(rateClasses[index].channels, channel)
+	}
+	if len(rateClasses) > 1 {
+		var paths []string
+		for _, rateClass := range rateClasses {
+			paths = append(paths, rateClass.channels[0].object.Path)
+		}
+		return nil, oops.
+			In("ReadData").
+			With("segmentOffset", segment.Offset).
+			With("paths", paths).
+			Errorf("unsupported DAQmx raw data layout: channels with different increments or raw data layouts in one segment")
+	}
+
+	rawDataSize := segment.LeadIn.NextSegmentOffset - segment.LeadIn.RawDataOffset
+	setDAQmxSampleCounts(rateClasses, rawDataSize)
+	return rateClasses, nil
+}
+
+// setDAQmxSampleCounts computes the number of samples of each rate class of a segment with the specified raw data size,
+// see daqmxRateClass for the layout.
+func setDAQmxSampleCounts(rateClasses []*daqmxRateClass, rawDataSize uint64) {
+	var chunkByteSize uint64
+	for _, rateClass := range rateClasses {
+		chunkByteSize += rateClass.chunkSize * rateClass.sampleByteSize()
+	}
+	if chunkByteSize == 0 {
+		return
+	}
+	chunkCount := rawDataSize / chunkByteSize
+	remainingByteSize := rawDataSize % chunkByteSize
+	for _, rateClass := range rateClasses {
+		rateClass.sampleCount = chunkCount * rateClass.chunkSize
+		sampleByteSize := rateClass.sampleByteSize()
+		if sampleByteSize == 0 {
+			continue
+		}
+		// the incomplete last chunk
+		sampleCount := min(rateClass.chunkSize, remainingByteSize/sampleByteSize)
+		rateClass.sampleCount += sampleCount
+		remainingByteSize -= sampleCount * sampleByteSize
+	}
+}
+
+// readDAQmxData reads the raw data of the DAQmx segment. Each chunk handled contains the samples of the channels of a single rate class
+// within a chunk of the segment.
+func (file *File) readDAQmxData(segment *Segment, readOptions *readOptions, chunkHandler func(chunk Chunk) error) error {
+	rateClasses, err := file.getDAQmxRateClasses(segment, readOptions)
+	if err != nil {
+		return err
+	}
+	valueReader := segment.LeadIn.ToC.ValueReader()
+	remainingSampleCounts := make([]uint64, len(rateClasses))
+	for i, rateClass := range rateClasses {
+		remainingSampleCounts[i] = rateClass.sampleCount
+	}
+	for {
+		done := true
+		for rateClassNo, rateClass := range rateClasses {
+			chunkSampleCount := min(rateClass.chunkSize, remainingSampleCounts[rateClassNo])
+			if chunkSampleCount == 0 {
+				continue
+			}
+			done = false
+			remainingSampleCounts[rateClassNo] -= chunkSampleCount
+
+			var chunk Chunk
+			for _, channel := range rateClass.channels {
+				chunk.Channels = append(chunk.Channels, ChannelData{
+					Path:               channel.object.Path,
+					Node:               channel.node,
+					WaveformAttributes: channel.waveformAttributes,
+					Shape:              sampleShape(rateClass.arrayDimension),
+					Samples:            make([]float64, chunkSampleCount*uint64(rateClass.arrayDimension)),
+				})
+			}
+			for j := 0; j < int(chunkSampleCount*uint64(rateClass.arrayDimension)); j++ {
+				for _, buffer := range rateClass.buffers {
+					_, err := io.ReadFull(file.r, buffer)
+					if err != nil {
+						return err
+					}
+				}
+				for channelNo, channel := range rateClass.channels {
+					firstScaler := channel.rawDataIndex.Scalers[0].(*DAQmxFormatChangingScaler)
+					v0, err := firstScaler.ReadFromBuffer(valueReader, rateClass.buffers)
+					if err != nil {
+						return err
+					}
+					v, err := utils.AsFloat64(v0)
+					if err != nil {
+						return err
+					}
+					v, err = readOptions.applyScalers(channel.scalers[1:], v)
+					if err != nil {
+						return err
+					}
+					chunk.Channels[channelNo].Samples[j] = v
+				}
+			}
+			fileOffset, err := file.r.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return err
+			}
+			chunk.FileOffset = fileOffset
+			err = chunkHandler(chunk)
+			if err != nil {
+				return err
+			}
+		}
+		if done {
+			return nil
+		}
+	}
+}
+
+// RateClasses returns the channels with raw data, grouped by increment in order of appearance.
+func (file *File) RateClasses() ([]RateClass, error) {
+	var rateClasses []RateClass
+	if file.root == nil {
+		return rateClasses, nil
+	}
+	for _, group := range file.root.Children() {
+		for _, channel := range group.Children() {
+			if file.rawDataIndexMap[channel.Path()] == nil {
+				continue
+			}
+			waveformAttributes, err := GetWaveformAttributes(channel.Properties().Collect())
+			if err != nil {
+				return nil, err
+			}
+			index := slices.IndexFunc(rateClasses, func(rateClass RateClass) bool {
+				return rateClass.Increment == waveformAttributes.Increment
+			})
+			if index < 0 {
+				rateClasses = append(rateClasses, RateClass{Increment: waveformAttributes.Increment})
+				index = len(rateClasses) - 1
+			}
+			rateClasses[index].Paths = append(rateClasses[index].Paths, channel.Path())
+		}
+	}
+	return rateClasses, nil
+}
@@ -0,0 +1,166 @@
+package tdms
+
+import (
+	"testing"
+
+	"github.com/ngyewch/tdms-go/internal/testfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadDAQmxData(t *testing.T) {
+	startTime := Timestamp{Seconds: 3787000000}
+	root := testfile.Object{Path: "/"}
+	group := testfile.Object{Path: "/'g'"}
+	{
+		// a single rate class, with two raw buffers and an incomplete last chunk
+		file := openTestFile(t, testfile.Segment{
+			ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData | testfile.TocDAQmxRawData,
+			Objects: []testfile.Object{
+				root,
+				group,
+				{
+					Path:         "/'g'/'a'",
+					RawDataIndex: testDAQmxRawDataIndex(2, 1, []testDAQmxScaler{{daqmxDataType: 3, rawBufferIndex: 0}}, []uint32{2, 4}),
+					Properties:   testfile.WaveformProperties(testfile.Timestamp(startTime), 0.1),
+				},
+				{
+					Path:         "/'g'/'b'",
+					RawDataIndex: testDAQmxRawDataIndex(2, 1, []testDAQmxScaler{{daqmxDataType: 5, rawBufferIndex: 1}}, []uint32{2, 4}),
+					Properties:   testfile.WaveformProperties(testfile.Timestamp(startTime), 0.1),
+				},
+			},
+			RawData: testfile.RawData(
+				int16(1), int32(10), int16(2), int32(20),
+				int16(3), int32(30), int16(4), int32(40),
+				int16(5), int32(50),
+			),
+		})
+		assert.Equal(t, map[string][]float64{
+			"/'g'/'a'": {1, 2, 3, 4, 5},
+			"/'g'/'b'": {10, 20, 30, 40, 50},
+		}, readTestSamples(t, file))
+		sampleCount, err := file.GetSampleCount()
+		if assert.NoError(t, err) {
+			assert.Equal(t, uint64(5), sampleCount)
+		}
+	}
+	{
+		// two rate classes, whose raw data layout is not known
+		file := openTestFile(t, testfile.Segment{
+			ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData | testfile.TocDAQmxRawData,
+			Objects: []testfile.Object{
+				root,
+				group,
+				{
+					Path:         "/'g'/'fast'",
+					RawDataIndex: testDAQmxRawDataIndex(2, 1, []testDAQmxScaler{{daqmxDataType: 3, rawBufferIndex: 0}}, []uint32{2}),
+					Properties:   testfile.WaveformProperties(testfile.Timestamp(startTime), 0.1),
+				},
+				{
+					Path:         "/'g'/'slow'",
+					RawDataIndex: testDAQmxRawDataIndex(1, 1, []testDAQmxScaler{{daqmxDataType: 5, rawBufferIndex: 0}}, []uint32{4}),
+					Properties:   testfile.WaveformProperties(testfile.Timestamp(startTime), 0.2),
+				},
+			},
+			RawData: testfile.RawData([]int16{1, 2}, int32(10)),
+		})
+		err := file.ReadData(func(chunk Chunk) error {
+			return nil
+		})
+		assert.ErrorContains(t, err, "unsupported DAQmx raw data layout")
+		_, err = file.GetChannelSampleCounts()
+		assert.ErrorContains(t, err, "unsupported DAQmx raw data layout")
+	}
+	{
+		// the increment of a channel changed by a later segment, which does not split the channels of the first segment
+		file := openTestFile(t,
+			testfile.Segment{
+				ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData | testfile.TocDAQmxRawData,
+				Objects: []testfile.Object{
+					root,
+					group,
+					{
+						Path:         "/'g'/'a'",
+						RawDataIndex: testDAQmxRawDataIndex(2, 1, []testDAQmxScaler{{daqmxDataType: 3, rawBufferIndex: 0}}, []uint32{4}),
+						Properties:   testfile.WaveformProperties(testfile.Timestamp(startTime), 0.1),
+					},
+					{
+						Path:         "/'g'/'b'",
+						RawDataIndex: testDAQmxRawDataIndex(2, 1, []testDAQmxScaler{{daqmxDataType: 3, rawBufferIndex: 0, byteOffset: 2}}, []uint32{4}),
+						Properties:   testfile.WaveformProperties(testfile.Timestamp(startTime), 0.1),
+					},
+				},
+				RawData: testfile.RawData([]int16{1, 10, 2, 20}),
+			},
+			testfile.Segment{
+				ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData | testfile.TocDAQmxRawData,
+				Objects: []testfile.Object{
+					{
+						Path:         "/'g'/'b'",
+						RawDataIndex: testDAQmxRawDataIndex(2, 1, []testDAQmxScaler{{daqmxDataType: 3, rawBufferIndex: 0}}, []uint32{2}),
+						Properties:   []testfile.Property{{Name: "wf_increment", Value: 0.2}},
+					},
+				},
+				RawData: testfile.RawData([]int16{30, 40}),
+			},
+		)
+		increments := make(map[string][]float64)
+		samples := make(map[string][]float64)
+		err := file.ReadData(func(chunk Chunk) error {
+			for _, channel := range chunk.Channels {
+				increments[channel.Path] = append(increments[channel.Path], channel.WaveformAttributes.Increment)
+				samples[channel.Path] = append(samples[channel.Path], channel.Samples...)
+			}
+			return nil
+		})
+		if assert.NoError(t, err) {
+			assert.Equal(t, map[string][]float64{
+				"/'g'/'a'": {1, 2},
+				"/'g'/'b'": {10, 20, 30, 40},
+			}, samples)
+			assert.Equal(t, map[string][]float64{
+				"/'g'/'a'": {0.1},
+				"/'g'/'b'": {0.1, 0.2},
+			}, increments)
+		}
+	}
+	{
+		// array dimension 2, each sample consisting of two records of the raw buffer
+		file := openTestFile(t, testfile.Segment{
+			ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData | testfile.TocDAQmxRawData,
+			Objects: []testfile.Object{
+				root,
+				group,
+				{
+					Path:         "/'g'/'a'",
+					RawDataIndex: testDAQmxRawDataIndex(2, 2, []testDAQmxScaler{{daqmxDataType: 3, rawBufferIndex: 0}}, []uint32{4}),
+					Properties:   testfile.WaveformProperties(testfile.Timestamp(startTime), 0.1),
+				},
+				{
+					Path:         "/'g'/'b'",
+					RawDataIndex: testDAQmxRawDataIndex(2, 2, []testDAQmxScaler{{daqmxDataType: 3, rawBufferIndex: 0, byteOffset: 2}}, []uint32{4}),
+					Properties:   testfile.WaveformProperties(testfile.Timestamp(startTime), 0.1),
+				},
+			},
+			RawData: testfile.RawData(
+				[]int16{1, 10, 2, 20},
+				[]int16{3, 30, 4, 40},
+			),
+		})
+		var channels []ChannelData
+		err := file.ReadData(func(chunk Chunk) error {
+			channels = append(channels, chunk.Channels...)
+			return nil
+		})
+		if assert.NoError(t, err) && assert.Len(t, channels, 2) {
+			assert.Equal(t, []int{2}, channels[0].Shape)
+			assert.Equal(t, []float64{1, 2, 3, 4}, channels[0].Samples)
+			assert.Equal(t, []float64{10, 20, 30, 40}, channels[1].Samples)
+			assert.Equal(t, []float64{30, 40}, channels[1].Sample(1))
+		}
+		sampleCounts, err := file.GetChannelSampleCounts()
+		if assert.NoError(t, err) {
+			assert.Equal(t, map[string]uint64{"/'g'/'a'": 2, "/'g'/'b'": 2}, sampleCounts)
+		}
+	}
+}
@@ -50,6 +50,9 @@ func (index *DAQmxRawDataIndex) CheckCompatibility(rawDataIndex RawDataIndex) er
 		if index.ChunkSize != otherIndex.ChunkSize {
 			return fmt.Errorf("chunk size mismatch")
 		}
+		if index.ArrayDimension != otherIndex.ArrayDimension {
+			return fmt.Errorf("array dimension mismatch")
+		}
 		if len(index.RawDataWidths) != len(otherIndex.RawDataWidths) {
 			return fmt.Errorf("raw data widths mismatch")
 		}
@@ -76,7 +79,7 @@ func ReadDAQmxRawDataIndex(r io.Reader, valueReader *ValueReader) (*DAQmxRawData
 	if err != nil {
 		return nil, err
 	}
-	if daqmxRawDataIndex.ArrayDimension != 1 {
+	if daqmxRawDataIndex.ArrayDimension == 0 {
 		return nil, oops.
 			In("DAQmxRawDataIndex").
 			With("arrayDimension", daqmxRawDataIndex.ArrayDimension).
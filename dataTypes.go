@@ -53,10 +53,20 @@ func (dataType DataType) SizeInBytes() int {
 		return 4
 	case DataTypeDoubleFloat:
 		return 8
+	case DataTypeExtendedFloat:
+		return extendedFloatSizeInBytes
+	case DataTypeSingleFloatWithUnit:
+		return 4
+	case DataTypeDoubleFloatWithUnit:
+		return 8
+	case DataTypeExtendedFloatWithUnit:
+		return extendedFloatSizeInBytes
 	case DataTypeBoolean:
 		return 1
 	case DataTypeTimestamp:
 		return 16
+	case DataTypeFixedPoint:
+		return fixedPointSizeInBytes
 	case DataTypeComplexSingleFloat:
 		return 8
 	case DataTypeComplexDoubleFloat:
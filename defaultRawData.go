@@ -0,0 +1,182 @@
+package tdms
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/ngyewch/tdms-go/utils"
+	"github.com/samber/oops"
+)
+
+type defaultChannel struct {
+	object             *Object
+	node               *Node
+	rawDataIndex       RawDataIndex
+	waveformAttributes *WaveformAttributes
+	scalers            []Scaler
+	fixedPointFormat   *FixedPointFormat
+}
+
+func (file *File) getDefaultChannels(segment *Segment, readOptions *readOptions) ([]defaultChannel, uint64, error) {
+	var channels []defaultChannel
+	var chunkByteSize uint64
+	for _, object := range segment.MetaData.Objects() {
+		if object.RawDataIndex == nil {
+			continue
+		}
+		if len(channels) > 0 {
+			err := channels[0].rawDataIndex.CheckCompatibility(object.RawDataIndex)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+		dataType := object.RawDataIndex.GetDataType()
+		if dataType.SizeInBytes() <= 0 {
+			return nil, 0, oops.
+				In("ReadData").
+				With("objectPath", object.Path).
+				With("dataType", dataType.String()).
+				Errorf("unsupported raw data type")
+		}
+		node := file.Node(object.Path)
+		if node == nil {
+			return nil, 0, fmt.Errorf("could not find object node")
+		}
+		props := node.Properties().Collect()
+		waveformAttributes, err := GetWaveformAttributes(props)
+		if err != nil {
+			return nil, 0, err
+		}
+		scalers, err := GetScalers(props)
+		if err != nil {
+			return nil, 0, err
+		}
+		var fixedPointFormat *FixedPointFormat
+		if dataType == DataTypeFixedPoint {
+			fixedPointFormat = readOptions.getFixedPointFormat(object.Path)
+		}
+		channels = append(channels, defaultChannel{
+			object:             object,
+			node:               node,
+			rawDataIndex:       object.RawDataIndex,
+			waveformAttributes: waveformAttributes,
+			scalers:            readOptions.getScalers(object.Path, scalers),
+			fixedPointFormat:   fixedPointFormat,
+		})
+		chunkByteSize += object.RawDataIndex.GetTotalSizeInBytes()
+	}
+	return channels, chunkByteSize, nil
+}
+
+func (file *File) getDefaultSampleCount(segment *Segment) (uint64, error) {
+	channels, chunkByteSize, err := file.getDefaultChannels(segment, newReadOptions())
+	if err != nil {
+		return 0, err
+	}
+	if (len(channels) == 0) || (chunkByteSize == 0) {
+		return 0, nil
+	}
+	rawDataSize := segment.LeadIn.NextSegmentOffset - segment.LeadIn.RawDataOffset
+	return (rawDataSize / chunkByteSize) * channels[0].rawDataIndex.GetChunkSize(), nil
+}
+
+func (file *File) readDefaultData(segment *Segment, readOptions *readOptions, chunkHandler func(chunk Chunk) error) error {
+	channels, chunkByteSize, err := file.getDefaultChannels(segment, readOptions)
+	if err != nil {
+		return err
+	}
+	if (len(channels) == 0) || (chunkByteSize == 0) {
+		return nil
+	}
+	interleaved := segment.LeadIn.ToC.InterleavedData()
+	if interleaved {
+		for _, channel := range channels[1:] {
+			if channel.rawDataIndex.GetChunkSize() != channels[0].rawDataIndex.GetChunkSize() {
+				return fmt.Errorf("chunk size mismatch")
+			}
+		}
+	}
+
+	valueReader := segment.LeadIn.ToC.ValueReader()
+	rawDataSize := segment.LeadIn.NextSegmentOffset - segment.LeadIn.RawDataOffset
+	chunkCount := rawDataSize / chunkByteSize
+	buffer := make([]byte, chunkByteSize)
+
+	for _, channel := range channels {
+		if (channel.rawDataIndex.GetDataType() == DataTypeFixedPoint) && (channel.fixedPointFormat == nil) {
+			return oops.
+				In("ReadData").
+				With("objectPath", channel.object.Path).
+				Errorf("unsupported fixed-point layout")
+		}
+	}
+
+	readSample := func(r io.Reader, channel defaultChannel) (float64, error) {
+		var v0 any
+		var err error
+		if channel.fixedPointFormat != nil {
+			v0, err = valueReader.ReadFixedPoint(r, *channel.fixedPointFormat)
+		} else {
+			v0, err = valueReader.ReadValueForDataType(r, channel.rawDataIndex.GetDataType())
+		}
+		if err != nil {
+			return 0, err
+		}
+		v, err := utils.AsFloat64(v0)
+		if err != nil {
+			return 0, err
+		}
+		return readOptions.applyScalers(channel.scalers, v)
+	}
+
+	for chunkNo := uint64(0); chunkNo < chunkCount; chunkNo++ {
+		_, err = io.ReadFull(file.r, buffer)
+		if err != nil {
+			return err
+		}
+		var chunk Chunk
+		for _, channel := range channels {
+			chunk.Channels = append(chunk.Channels, ChannelData{
+				Path:               channel.object.Path,
+				Node:               channel.node,
+				WaveformAttributes: channel.waveformAttributes,
+				Shape:              sampleShape(channel.rawDataIndex.GetArrayDimension()),
+				Samples:            make([]float64, channel.rawDataIndex.GetChunkSize()*uint64(channel.rawDataIndex.GetArrayDimension())),
+			})
+		}
+		r := bytes.NewReader(buffer)
+		if interleaved {
+			for i := 0; i < chunk.Channels[0].SampleCount(); i++ {
+				for channelNo, channel := range channels {
+					sample := chunk.Channels[channelNo].Sample(i)
+					for j := range sample {
+						sample[j], err = readSample(r, channel)
+						if err != nil {
+							return err
+						}
+					}
+				}
+			}
+		} else {
+			for channelNo, channel := range channels {
+				for i := range chunk.Channels[channelNo].Samples {
+					chunk.Channels[channelNo].Samples[i], err = readSample(r, channel)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+		fileOffset, err := file.r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		chunk.FileOffset = fileOffset
+		err = chunkHandler(chunk)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package tdms
+
+import (
+	"testing"
+
+	"github.com/ngyewch/tdms-go/internal/testfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadDefaultData(t *testing.T) {
+	root := testfile.Object{Path: "/"}
+	group := testfile.Object{Path: "/'g'"}
+	{
+		// contiguous: two chunks of 2 samples, the values of each channel stored one after another within each chunk
+		file := openTestFile(t, testfile.Segment{
+			ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData,
+			Objects: []testfile.Object{
+				root,
+				group,
+				{Path: "/'g'/'a'", RawDataIndex: testfile.RawDataIndex(DataTypeI16, 1, 2)},
+				{Path: "/'g'/'b'", RawDataIndex: testfile.RawDataIndex(DataTypeDoubleFloat, 1, 2)},
+			},
+			RawData: testfile.RawData(
+				[]int16{1, 2}, []float64{0.5, 1.5},
+				[]int16{3, 4}, []float64{2.5, 3.5},
+			),
+		})
+		assert.Equal(t, map[string][]float64{
+			"/'g'/'a'": {1, 2, 3, 4},
+			"/'g'/'b'": {0.5, 1.5, 2.5, 3.5},
+		}, readTestSamples(t, file))
+		sampleCounts, err := file.GetChannelSampleCounts()
+		if assert.NoError(t, err) {
+			assert.Equal(t, map[string]uint64{"/'g'/'a'": 4, "/'g'/'b'": 4}, sampleCounts)
+		}
+	}
+	{
+		// interleaved, with extended precision floats
+		extendedFloats := [][]byte{
+			{0, 0, 0, 0, 0, 0, 0, 0x80, 0xff, 0x3f},
+			{0, 0, 0, 0, 0, 0, 0, 0xc0, 0x00, 0x40},
+		}
+		file := openTestFile(t, testfile.Segment{
+			ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData | testfile.TocInterleavedData,
+			Objects: []testfile.Object{
+				root,
+				group,
+				{Path: "/'g'/'a'", RawDataIndex: testfile.RawDataIndex(DataTypeU8, 1, 2)},
+				{Path: "/'g'/'b'", RawDataIndex: testfile.RawDataIndex(DataTypeExtendedFloat, 1, 2)},
+			},
+			RawData: testfile.RawData(
+				uint8(10), extendedFloats[0],
+				uint8(20), extendedFloats[1],
+			),
+		})
+		assert.Equal(t, map[string][]float64{
+			"/'g'/'a'": {10, 20},
+			"/'g'/'b'": {1, 3},
+		}, readTestSamples(t, file))
+	}
+	{
+		// a segment without metadata reuses the object list of the previous segment, and a segment without kTocNewObjList
+		// extends the object list of the previous segment
+		file := openTestFile(t,
+			testfile.Segment{
+				ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData,
+				Objects: []testfile.Object{
+					root,
+					group,
+					{Path: "/'g'/'a'", RawDataIndex: testfile.RawDataIndex(DataTypeI32, 1, 1)},
+				},
+				RawData: testfile.RawData(int32(1)),
+			},
+			testfile.Segment{
+				ToC:     testfile.TocRawData,
+				RawData: testfile.RawData(int32(2), int32(3)),
+			},
+			testfile.Segment{
+				ToC: testfile.TocMetaData | testfile.TocRawData,
+				Objects: []testfile.Object{
+					{Path: "/'g'/'b'", RawDataIndex: testfile.RawDataIndex(DataTypeI32, 1, 1)},
+				},
+				RawData: testfile.RawData(int32(4), int32(40)),
+			},
+			testfile.Segment{
+				ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData,
+				Objects: []testfile.Object{
+					{Path: "/'g'/'b'", RawDataIndex: testfile.RawDataIndexSameAsPreviousSegment},
+				},
+				RawData: testfile.RawData(int32(50)),
+			},
+		)
+		assert.Equal(t, map[string][]float64{
+			"/'g'/'a'": {1, 2, 3, 4},
+			"/'g'/'b'": {40, 50},
+		}, readTestSamples(t, file))
+		sampleCount, err := file.GetSampleCount()
+		if assert.NoError(t, err) {
+			assert.Equal(t, uint64(5), sampleCount)
+		}
+	}
+	{
+		// array dimension 2, contiguous and interleaved
+		for _, toc := range []uint32{0, testfile.TocInterleavedData} {
+			rawData := testfile.RawData([]int16{1, 2, 3, 4}, []int32{10, 20, 30, 40})
+			if toc != 0 {
+				rawData = testfile.RawData(int16(1), int16(2), int32(10), int32(20), int16(3), int16(4), int32(30), int32(40))
+			}
+			file := openTestFile(t, testfile.Segment{
+				ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData | toc,
+				Objects: []testfile.Object{
+					root,
+					group,
+					{Path: "/'g'/'a'", RawDataIndex: testfile.RawDataIndex(DataTypeI16, 2, 2)},
+					{Path: "/'g'/'b'", RawDataIndex: testfile.RawDataIndex(DataTypeI32, 2, 2)},
+				},
+				RawData: rawData,
+			})
+			var channels []ChannelData
+			err := file.ReadData(func(chunk Chunk) error {
+				channels = append(channels, chunk.Channels...)
+				return nil
+			})
+			if assert.NoError(t, err) && assert.Len(t, channels, 2) {
+				assert.Equal(t, []int{2}, channels[0].Shape)
+				assert.Equal(t, 2, channels[0].SampleCount())
+				assert.Equal(t, []float64{1, 2, 3, 4}, channels[0].Samples)
+				assert.Equal(t, []float64{3, 4}, channels[0].Sample(1))
+				assert.Equal(t, []float64{10, 20, 30, 40}, channels[1].Samples)
+				assert.Equal(t, []float64{10, 20}, channels[1].Sample(0))
+			}
+			sampleCounts, err := file.GetChannelSampleCounts()
+			if assert.NoError(t, err) {
+				assert.Equal(t, map[string]uint64{"/'g'/'a'": 2, "/'g'/'b'": 2}, sampleCounts)
+			}
+			assert.Equal(t, uint32(2), file.ChannelArrayDimension("/'g'/'a'"))
+		}
+	}
+}
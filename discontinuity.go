@@ -0,0 +1,110 @@
+package tdms
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+type DiscontinuityType int
+
+const (
+	// DiscontinuityGap indicates that a segment starts later than the end of the previous samples.
+	DiscontinuityGap DiscontinuityType = iota
+	// DiscontinuityOverlap indicates that a segment starts earlier than the end of the previous samples.
+	DiscontinuityOverlap
+	// DiscontinuitySampleCountMismatch indicates that the number of samples in a segment differs from wf_samples.
+	DiscontinuitySampleCountMismatch
+)
+
+func (discontinuityType DiscontinuityType) String() string {
+	switch discontinuityType {
+	case DiscontinuityGap:
+		return "gap"
+	case DiscontinuityOverlap:
+		return "overlap"
+	case DiscontinuitySampleCountMismatch:
+		return "sample-count-mismatch"
+	default:
+		return fmt.Sprintf("DiscontinuityType(%d)", int(discontinuityType))
+	}
+}
+
+// Discontinuity describes a discontinuity of a channel at the start of a segment.
+type Discontinuity struct {
+	Type          DiscontinuityType
+	Path          string
+	SegmentIndex  int
+	SegmentOffset int64
+	// SampleIndex is the index of the first sample of the segment within the channel.
+	SampleIndex uint64
+	// ExpectedStartTime is the time following the last sample of the previous segment.
+	ExpectedStartTime Timestamp
+	// StartTime is the time of the first sample of the segment, as specified by wf_start_time and wf_start_offset.
+	StartTime Timestamp
+	// Duration is StartTime - ExpectedStartTime in seconds; positive for gaps, negative for overlaps.
+	Duration            float64
+	ExpectedSampleCount uint64
+	SampleCount         uint64
+}
+
+// FindDiscontinuities walks the segments of the file and reports, for each channel, time discontinuities where a segment
+// restates wf_start_time, and segments whose sample count differs from wf_samples.
+// Time differences not exceeding the tolerance (in seconds) are ignored; a tolerance <= 0 uses half the channel's increment.
+func (file *File) FindDiscontinuities(tolerance float64) ([]Discontinuity, error) {
+	timingMap, err := file.getSegmentTimings()
+	if err != nil {
+		return nil, err
+	}
+	var discontinuities []Discontinuity
+	for _, timings := range timingMap {
+		for _, timing := range timings {
+			path := timing.path
+			if timing.restarted {
+				duration := timing.startTime.SubSeconds(timing.expectedStartTime)
+				segmentTolerance := tolerance
+				if segmentTolerance <= 0 {
+					segmentTolerance = timing.waveformAttributes.Increment / 2
+				}
+				if math.Abs(duration) > segmentTolerance {
+					discontinuityType := DiscontinuityGap
+					if duration < 0 {
+						discontinuityType = DiscontinuityOverlap
+					}
+					discontinuities = append(discontinuities, Discontinuity{
+						Type:              discontinuityType,
+						Path:              path,
+						SegmentIndex:      timing.segmentIndex,
+						SegmentOffset:     timing.segmentOffset,
+						SampleIndex:       timing.sampleIndex,
+						ExpectedStartTime: timing.expectedStartTime,
+						StartTime:         timing.startTime,
+						Duration:          duration,
+						SampleCount:       timing.sampleCount,
+					})
+				}
+			}
+			samples := timing.waveformAttributes.Samples
+			if (samples > 0) && (uint64(samples) != timing.sampleCount) {
+				discontinuities = append(discontinuities, Discontinuity{
+					Type:                DiscontinuitySampleCountMismatch,
+					Path:                path,
+					SegmentIndex:        timing.segmentIndex,
+					SegmentOffset:       timing.segmentOffset,
+					SampleIndex:         timing.sampleIndex,
+					StartTime:           timing.startTime,
+					ExpectedSampleCount: uint64(samples),
+					SampleCount:         timing.sampleCount,
+				})
+			}
+		}
+	}
+	slices.SortStableFunc(discontinuities, func(a, b Discontinuity) int {
+		if a.SegmentIndex != b.SegmentIndex {
+			return cmp.Compare(a.SegmentIndex, b.SegmentIndex)
+		}
+		return cmp.Compare(a.Path, b.Path)
+	})
+	return discontinuities, nil
+}
@@ -0,0 +1,105 @@
+package tdms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngyewch/tdms-go/internal/testfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindDiscontinuities(t *testing.T) {
+	startTime := NewTimestamp(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	path := "/'g'/'a'"
+	segment := func(start Timestamp, properties ...testfile.Property) testfile.Segment {
+		return testfile.Segment{
+			ToC: testfile.TocMetaData | testfile.TocRawData,
+			Objects: []testfile.Object{
+				{
+					Path:         path,
+					RawDataIndex: testfile.RawDataIndex(DataTypeDoubleFloat, 1, 2),
+					Properties:   append(testfile.WaveformProperties(testfile.Timestamp(start), 1), properties...),
+				},
+			},
+			RawData: testfile.RawData([]float64{0, 0}),
+		}
+	}
+	first := segment(startTime, testfile.Property{Name: "wf_samples", Value: int32(3)})
+	first.ToC |= testfile.TocNewObjList
+	first.Objects = append([]testfile.Object{{Path: "/"}, {Path: "/'g'"}}, first.Objects...)
+	file := openTestFile(t,
+		// 2 samples, although wf_samples is 3
+		first,
+		// a restart at the expected time, which is not a discontinuity
+		segment(startTime.AddSeconds(2), testfile.Property{Name: "wf_samples", Value: int32(2)}),
+		// a gap of 2s
+		segment(startTime.AddSeconds(6)),
+		// an overlap of 0.25s
+		segment(startTime.AddSeconds(7.75)),
+		// no restart
+		testfile.Segment{
+			ToC:     testfile.TocRawData,
+			RawData: testfile.RawData([]float64{0, 0}),
+		},
+	)
+	sampleCountMismatch := Discontinuity{
+		Type:                DiscontinuitySampleCountMismatch,
+		Path:                path,
+		SegmentIndex:        0,
+		SegmentOffset:       0,
+		StartTime:           startTime,
+		ExpectedSampleCount: 3,
+		SampleCount:         2,
+	}
+	gap := Discontinuity{
+		Type:              DiscontinuityGap,
+		Path:              path,
+		SegmentIndex:      2,
+		SampleIndex:       4,
+		ExpectedStartTime: startTime.AddSeconds(4),
+		StartTime:         startTime.AddSeconds(6),
+		Duration:          2,
+		SampleCount:       2,
+	}
+	overlap := Discontinuity{
+		Type:              DiscontinuityOverlap,
+		Path:              path,
+		SegmentIndex:      3,
+		SampleIndex:       6,
+		ExpectedStartTime: startTime.AddSeconds(8),
+		StartTime:         startTime.AddSeconds(7.75),
+		Duration:          -0.25,
+		SampleCount:       2,
+	}
+	withSegmentOffsets := func(discontinuities []Discontinuity, actual []Discontinuity) []Discontinuity {
+		// the segment offsets depend on the metadata sizes, and are checked to increase
+		for i := range discontinuities {
+			if i < len(actual) {
+				discontinuities[i].SegmentOffset = actual[i].SegmentOffset
+			}
+		}
+		return discontinuities
+	}
+	{
+		// the default tolerance is half the increment
+		discontinuities, err := file.FindDiscontinuities(0)
+		if assert.NoError(t, err) {
+			assert.Equal(t, withSegmentOffsets([]Discontinuity{sampleCountMismatch, gap}, discontinuities), discontinuities)
+			assert.Equal(t, int64(0), discontinuities[0].SegmentOffset)
+			assert.Greater(t, discontinuities[1].SegmentOffset, int64(0))
+		}
+	}
+	{
+		discontinuities, err := file.FindDiscontinuities(0.1)
+		if assert.NoError(t, err) {
+			assert.Equal(t, withSegmentOffsets([]Discontinuity{sampleCountMismatch, gap, overlap}, discontinuities), discontinuities)
+			assert.Greater(t, discontinuities[2].SegmentOffset, discontinuities[1].SegmentOffset)
+		}
+	}
+	{
+		discontinuities, err := file.FindDiscontinuities(5)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []Discontinuity{sampleCountMismatch}, discontinuities)
+		}
+	}
+}
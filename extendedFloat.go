@@ -0,0 +1,112 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+)
+
+const (
+	extendedFloatSizeInBytes = 10
+	extendedFloatBias        = 16383
+	extendedFloatMaxExponent = 0x7fff
+)
+
+// ExtendedFloat is an IEEE 754 80-bit extended precision floating point value.
+type ExtendedFloat struct {
+	Sign     bool
+	Exponent uint16
+	Mantissa uint64
+}
+
+// DecodeExtendedFloat decodes an IEEE 754 80-bit extended precision floating point value.
+func DecodeExtendedFloat(b []byte, byteOrder binary.ByteOrder) (ExtendedFloat, error) {
+	if len(b) < extendedFloatSizeInBytes {
+		return ExtendedFloat{}, fmt.Errorf("insufficient bytes for extended float")
+	}
+	var signAndExponent uint16
+	var mantissa uint64
+	switch byteOrder {
+	case binary.LittleEndian:
+		mantissa = binary.LittleEndian.Uint64(b[0:8])
+		signAndExponent = binary.LittleEndian.Uint16(b[8:10])
+	case binary.BigEndian:
+		signAndExponent = binary.BigEndian.Uint16(b[0:2])
+		mantissa = binary.BigEndian.Uint64(b[2:10])
+	default:
+		return ExtendedFloat{}, fmt.Errorf("unknown byte order")
+	}
+	return ExtendedFloat{
+		Sign:     signAndExponent&0x8000 != 0,
+		Exponent: signAndExponent & extendedFloatMaxExponent,
+		Mantissa: mantissa,
+	}, nil
+}
+
+func (f ExtendedFloat) IsNaN() bool {
+	return (f.Exponent == extendedFloatMaxExponent) && (f.Mantissa<<1 != 0)
+}
+
+func (f ExtendedFloat) IsInf() bool {
+	return (f.Exponent == extendedFloatMaxExponent) && (f.Mantissa<<1 == 0)
+}
+
+// binaryExponent returns the exponent e such that the value is Mantissa * 2^e.
+func (f ExtendedFloat) binaryExponent() int {
+	if f.Exponent == 0 {
+		// denormal
+		return 1 - extendedFloatBias - 63
+	}
+	return int(f.Exponent) - extendedFloatBias - 63
+}
+
+// Float64 returns the nearest float64 value.
+func (f ExtendedFloat) Float64() float64 {
+	if f.IsNaN() {
+		return math.NaN()
+	}
+	if f.IsInf() {
+		if f.Sign {
+			return math.Inf(-1)
+		}
+		return math.Inf(1)
+	}
+	v, _ := f.BigFloat().Float64()
+	return v
+}
+
+// BigFloat returns the exact value. NaN values are returned as nil.
+func (f ExtendedFloat) BigFloat() *big.Float {
+	if f.IsNaN() {
+		return nil
+	}
+	if f.IsInf() {
+		return new(big.Float).SetInf(f.Sign)
+	}
+	v := new(big.Float).SetPrec(64).SetUint64(f.Mantissa)
+	v.SetMantExp(v, f.binaryExponent())
+	if f.Sign {
+		v.Neg(v)
+	}
+	return v
+}
+
+func (vr *ValueReader) readExtendedFloat(r io.Reader) (ExtendedFloat, error) {
+	b := make([]byte, extendedFloatSizeInBytes)
+	_, err := io.ReadFull(r, b)
+	if err != nil {
+		return ExtendedFloat{}, err
+	}
+	return DecodeExtendedFloat(b, vr.byteOrder)
+}
+
+// ReadExtendedFloat reads an IEEE 754 80-bit extended precision floating point value, rounded to the nearest float64 value.
+func (vr *ValueReader) ReadExtendedFloat(r io.Reader) (float64, error) {
+	f, err := vr.readExtendedFloat(r)
+	if err != nil {
+		return 0, err
+	}
+	return f.Float64(), nil
+}
@@ -0,0 +1,66 @@
+package tdms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtendedFloat(t *testing.T) {
+	{
+		// 1.0
+		v, err := LittleEndianValueReader.ReadExtendedFloat(bytes.NewReader([]byte{0, 0, 0, 0, 0, 0, 0, 0x80, 0xff, 0x3f}))
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1.0, v)
+		}
+	}
+	{
+		// -2.5
+		v, err := BigEndianValueReader.ReadExtendedFloat(bytes.NewReader([]byte{0xc0, 0x00, 0xa0, 0, 0, 0, 0, 0, 0, 0}))
+		if assert.NoError(t, err) {
+			assert.Equal(t, -2.5, v)
+		}
+	}
+	{
+		// 1 + 2^-63 cannot be represented as float64
+		b := make([]byte, 10)
+		binary.LittleEndian.PutUint64(b[0:8], 0x8000000000000001)
+		binary.LittleEndian.PutUint16(b[8:10], 0x3fff)
+		v, err := LittleEndianValueReader.ReadExtendedFloat(bytes.NewReader(b))
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1.0, v)
+		}
+		f, err := DecodeExtendedFloat(b, binary.LittleEndian)
+		if assert.NoError(t, err) {
+			expected, _, err := big.ParseFloat("0x1.0000000000000002p0", 0, 64, big.ToNearestEven)
+			if assert.NoError(t, err) {
+				assert.Equal(t, 0, f.BigFloat().Cmp(expected))
+			}
+		}
+	}
+	{
+		f, err := DecodeExtendedFloat([]byte{0, 0, 0, 0, 0, 0, 0, 0x80, 0xff, 0xff}, binary.LittleEndian)
+		if assert.NoError(t, err) {
+			assert.True(t, f.IsInf())
+			assert.True(t, math.IsInf(f.Float64(), -1))
+		}
+	}
+	{
+		f, err := DecodeExtendedFloat([]byte{1, 0, 0, 0, 0, 0, 0, 0xc0, 0xff, 0x7f}, binary.LittleEndian)
+		if assert.NoError(t, err) {
+			assert.True(t, f.IsNaN())
+			assert.True(t, math.IsNaN(f.Float64()))
+			assert.Nil(t, f.BigFloat())
+		}
+	}
+	{
+		v, err := LittleEndianValueReader.ReadValueForDataType(bytes.NewReader([]byte{0, 0, 0, 0, 0, 0, 0, 0xc0, 0x00, 0x40}), DataTypeExtendedFloatWithUnit)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 3.0, v)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package tdms
+
+import (
+	"math"
+	"strconv"
+)
+
+// FileInfo describes the metadata of a file, for inspection and export.
+type FileInfo struct {
+	Properties []PropertyInfo `json:"properties" yaml:"properties"`
+	Groups     []GroupInfo    `json:"groups" yaml:"groups"`
+}
+
+// GroupInfo describes a group and its channels.
+type GroupInfo struct {
+	Name       string         `json:"name" yaml:"name"`
+	Path       string         `json:"path" yaml:"path"`
+	Properties []PropertyInfo `json:"properties" yaml:"properties"`
+	Channels   []ChannelInfo  `json:"channels" yaml:"channels"`
+}
+
+// ChannelInfo describes a channel. The waveform fields are only set for channels with a wf_increment property.
+type ChannelInfo struct {
+	Name           string     `json:"name" yaml:"name"`
+	Path           string     `json:"path" yaml:"path"`
+	DataType       string     `json:"dataType,omitempty" yaml:"dataType,omitempty"`
+	ArrayDimension uint32     `json:"arrayDimension,omitempty" yaml:"arrayDimension,omitempty"`
+	SampleCount    uint64     `json:"sampleCount" yaml:"sampleCount"`
+	StartTime      *Timestamp `json:"startTime,omitempty" yaml:"startTime,omitempty"`
+	Increment      float64    `json:"increment,omitempty" yaml:"increment,omitempty"`
+	SampleRate     float64    `json:"sampleRate,omitempty" yaml:"sampleRate,omitempty"`
+	// Duration is the duration of the channel in seconds, i.e. the sample count times the increment.
+	Duration   float64        `json:"duration,omitempty" yaml:"duration,omitempty"`
+	Unit       string         `json:"unit,omitempty" yaml:"unit,omitempty"`
+	Properties []PropertyInfo `json:"properties" yaml:"properties"`
+}
+
+// PropertyInfo describes a property. Type is the TDMS data type of the value.
+//
+// Value is typed so that it round-trips through JSON and YAML: integers stay integers, timestamps are ISO 8601
+// strings with full precision, non-finite floats are the strings "NaN", "+Inf" and "-Inf", and complex values are
+// [real, imaginary] pairs.
+type PropertyInfo struct {
+	Name  string `json:"name" yaml:"name"`
+	Type  string `json:"type" yaml:"type"`
+	Value any    `json:"value" yaml:"value"`
+}
+
+// Info returns a description of the metadata of the file, in file order. The description of a file without metadata is empty.
+func (file *File) Info() (*FileInfo, error) {
+	if file.Root() == nil {
+		return &FileInfo{}, nil
+	}
+	sampleCounts, err := file.GetChannelSampleCounts()
+	if err != nil {
+		return nil, err
+	}
+	info := &FileInfo{
+		Properties: newPropertyInfos(file.Root()),
+	}
+	for _, group := range file.Root().Children() {
+		groupInfo := GroupInfo{
+			Name:       group.Name(),
+			Path:       group.Path(),
+			Properties: newPropertyInfos(group),
+		}
+		for _, channel := range group.Children() {
+			channelInfo, err := file.newChannelInfo(channel, sampleCounts[channel.Path()])
+			if err != nil {
+				return nil, err
+			}
+			groupInfo.Channels = append(groupInfo.Channels, channelInfo)
+		}
+		info.Groups = append(info.Groups, groupInfo)
+	}
+	return info, nil
+}
+
+func (file *File) newChannelInfo(channel *Node, sampleCount uint64) (ChannelInfo, error) {
+	info := ChannelInfo{
+		Name:        channel.Name(),
+		Path:        channel.Path(),
+		SampleCount: sampleCount,
+		Properties:  newPropertyInfos(channel),
+	}
+	if dataType := file.ChannelDataType(channel.Path()); dataType != DataTypeVoid {
+		info.DataType = dataType.String()
+		info.ArrayDimension = file.ChannelArrayDimension(channel.Path())
+	}
+	waveformAttributes, err := GetWaveformAttributes(channel.Properties().Collect())
+	if err != nil {
+		return ChannelInfo{}, err
+	}
+	info.Unit = waveformAttributes.Unit
+	if waveformAttributes.Increment > 0 {
+		if !waveformAttributes.StartTime.IsZero() {
+			info.StartTime = &waveformAttributes.StartTime
+		}
+		info.Increment = waveformAttributes.Increment
+		info.SampleRate = waveformAttributes.SampleRate()
+		info.Duration = float64(sampleCount) * waveformAttributes.Increment
+	}
+	return info, nil
+}
+
+func newPropertyInfos(node *Node) []PropertyInfo {
+	propertyInfos := make([]PropertyInfo, 0, node.Properties().Len())
+	for name := range node.Properties().Keys() {
+		propertyInfo, _ := node.PropertyInfo(name)
+		propertyInfos = append(propertyInfos, propertyInfo)
+	}
+	return propertyInfos
+}
+
+// PropertyInfo describes the property of the node. Unlike NewPropertyInfo, Type is the data type the property is defined with in the file.
+func (node *Node) PropertyInfo(name string) (PropertyInfo, bool) {
+	value, exists := node.Property(name)
+	if !exists {
+		return PropertyInfo{}, false
+	}
+	info := NewPropertyInfo(name, value)
+	if dataType, exists := node.PropertyDataType(name); exists {
+		info.Type = dataType.String()
+	}
+	return info, true
+}
+
+// NewPropertyInfo describes a property value, as returned by Node.Property. Type is inferred from the Go type of the value,
+// so that e.g. extended float and *WithUnit values are described as DoubleFloat or SingleFloat; use Node.PropertyInfo for the data type defined in the file.
+func NewPropertyInfo(name string, value any) PropertyInfo {
+	info := PropertyInfo{
+		Name:  name,
+		Value: value,
+	}
+	var dataType DataType
+	switch v := value.(type) {
+	case int8:
+		dataType = DataTypeI8
+	case int16:
+		dataType = DataTypeI16
+	case int32:
+		dataType = DataTypeI32
+	case int64:
+		dataType = DataTypeI64
+	case uint8:
+		dataType = DataTypeU8
+	case uint16:
+		dataType = DataTypeU16
+	case uint32:
+		dataType = DataTypeU32
+	case uint64:
+		dataType = DataTypeU64
+	case float32:
+		dataType = DataTypeSingleFloat
+		info.Value = propertyFloatValue(float64(v))
+	case float64:
+		dataType = DataTypeDoubleFloat
+		info.Value = propertyFloatValue(v)
+	case string:
+		dataType = DataTypeString
+	case bool:
+		dataType = DataTypeBoolean
+	case Timestamp:
+		dataType = DataTypeTimestamp
+		info.Value = v.String()
+	case FixedPoint:
+		dataType = DataTypeFixedPoint
+		info.Value = propertyFloatValue(v.Float64())
+	case complex64:
+		dataType = DataTypeComplexSingleFloat
+		info.Value = []any{propertyFloatValue(float64(real(v))), propertyFloatValue(float64(imag(v)))}
+	case complex128:
+		dataType = DataTypeComplexDoubleFloat
+		info.Value = []any{propertyFloatValue(real(v)), propertyFloatValue(imag(v))}
+	default:
+		dataType = DataTypeVoid
+	}
+	info.Type = dataType.String()
+	return info
+}
+
+// propertyFloatValue returns non-finite values as strings, which JSON cannot represent as numbers.
+func propertyFloatValue(v float64) any {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return v
+}
@@ -0,0 +1,97 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/ngyewch/tdms-go/internal/testfile"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestNewPropertyInfo(t *testing.T) {
+	ts, err := ParseTimestamp("2024-01-01T00:00:00.123456789012Z")
+	if !assert.NoError(t, err) {
+		return
+	}
+	{
+		info := NewPropertyInfo("wf_start_time", ts)
+		assert.Equal(t, "TimeStamp", info.Type)
+		b, err := json.Marshal(info)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{"name":"wf_start_time","type":"TimeStamp","value":"2024-01-01T00:00:00.123456789012Z"}`, string(b))
+		}
+		parsed, err := ParseTimestamp(info.Value.(string))
+		if assert.NoError(t, err) {
+			assert.Equal(t, ts, parsed)
+		}
+	}
+	{
+		info := NewPropertyInfo("count", uint64(math.MaxUint64))
+		assert.Equal(t, "U64", info.Type)
+		b, err := json.Marshal(info)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{"name":"count","type":"U64","value":18446744073709551615}`, string(b))
+		}
+		b, err = yaml.Marshal(info)
+		if assert.NoError(t, err) {
+			var decoded struct {
+				Value uint64 `yaml:"value"`
+			}
+			assert.NoError(t, yaml.Unmarshal(b, &decoded))
+			assert.Equal(t, uint64(math.MaxUint64), decoded.Value)
+		}
+	}
+	{
+		info := NewPropertyInfo("gain", math.Inf(-1))
+		assert.Equal(t, "DoubleFloat", info.Type)
+		b, err := json.Marshal(info)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{"name":"gain","type":"DoubleFloat","value":"-Inf"}`, string(b))
+		}
+	}
+	{
+		info := NewPropertyInfo("z", complex(float32(1), float32(-2)))
+		assert.Equal(t, "ComplexSingleFloat", info.Type)
+		assert.Equal(t, []any{float64(1), float64(-2)}, info.Value)
+	}
+}
+
+func TestFileInfo(t *testing.T) {
+	{
+		// a file without metadata segments has no root
+		file := openTestFile(t)
+		info, err := file.Info()
+		if assert.NoError(t, err) {
+			assert.Equal(t, &FileInfo{}, info)
+		}
+	}
+	{
+		file := openTestFile(t,
+			testfile.Segment{
+				ToC: testfile.TocMetaData | testfile.TocNewObjList,
+				Objects: []testfile.Object{
+					{Path: "/", Properties: []testfile.Property{{Name: "name", Value: "test"}}},
+					{Path: "/'g'", Properties: []testfile.Property{
+						{Name: "extended", Value: testfile.Value{DataType: uint32(DataTypeExtendedFloat), Data: []byte{0, 0, 0, 0, 0, 0, 0, 0x80, 0xff, 0x3f}}},
+						{Name: "range", Value: testfile.Value{DataType: uint32(DataTypeDoubleFloatWithUnit), Data: binary.LittleEndian.AppendUint64(nil, math.Float64bits(2.5))}},
+						{Name: "gain", Value: float32(0.5)},
+					}},
+				},
+			},
+		)
+		info, err := file.Info()
+		if assert.NoError(t, err) {
+			assert.Equal(t, []PropertyInfo{{Name: "name", Type: "String", Value: "test"}}, info.Properties)
+			if assert.Len(t, info.Groups, 1) {
+				assert.Equal(t, []PropertyInfo{
+					{Name: "extended", Type: "ExtendedFloat", Value: float64(1)},
+					{Name: "range", Type: "DoubleFloatWithUnit", Value: 2.5},
+					{Name: "gain", Type: "SingleFloat", Value: float64(0.5)},
+				}, info.Groups[0].Properties)
+			}
+		}
+	}
+}
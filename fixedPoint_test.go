@@ -0,0 +1,73 @@
+package tdms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ngyewch/tdms-go/internal/testfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixedPoint(t *testing.T) {
+	{
+		// signed, 16-bit word length, 8-bit integer word length: 0xff80 = -0.5
+		format := FixedPointFormat{
+			Signed:            true,
+			WordLength:        16,
+			IntegerWordLength: 8,
+		}
+		b := binary.LittleEndian.AppendUint64(nil, 0xff80)
+		v, err := LittleEndianValueReader.ReadFixedPoint(bytes.NewReader(b), format)
+		if assert.NoError(t, err) {
+			assert.Equal(t, int64(-128), v.Word)
+			assert.Equal(t, -0.5, v.Float64())
+			assert.False(t, v.Overflow)
+		}
+	}
+	{
+		// unsigned, 16-bit word length, 8-bit integer word length, with overflow status: 0xff80 = 255.5
+		format := FixedPointFormat{
+			WordLength:            16,
+			IntegerWordLength:     8,
+			IncludeOverflowStatus: true,
+		}
+		b := binary.BigEndian.AppendUint64(nil, 0x1ff80)
+		v, err := BigEndianValueReader.ReadFixedPoint(bytes.NewReader(b), format)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 255.5, v.Float64())
+			assert.True(t, v.Overflow)
+		}
+	}
+	{
+		// the layout of the format stored with property values is not known
+		_, err := LittleEndianValueReader.ReadValueForDataType(bytes.NewReader(make([]byte, 13)), DataTypeFixedPoint)
+		assert.ErrorContains(t, err, "unsupported fixed-point layout")
+	}
+	{
+		_, err := LittleEndianValueReader.ReadFixedPoint(bytes.NewReader(make([]byte, 8)), FixedPointFormat{
+			WordLength:            64,
+			IncludeOverflowStatus: true,
+		})
+		assert.Error(t, err)
+	}
+	{
+		// fixed-point channel, with trailing bytes in the raw data index
+		file := openTestFile(t, testfile.Segment{
+			ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData,
+			Objects: []testfile.Object{
+				{Path: "/"},
+				{Path: "/'g'"},
+				{Path: "/'g'/'c'", RawDataIndex: append(binary.LittleEndian.AppendUint32(nil, 25), append(testfile.RawDataIndex(uint32(DataTypeFixedPoint), 1, 2)[4:], 1, 0, 16, 8, 0)...)},
+			},
+			RawData: testfile.RawData([]uint64{0xff80, 0x0180}),
+		})
+		err := file.ReadData(func(chunk Chunk) error {
+			return nil
+		})
+		assert.ErrorContains(t, err, "unsupported fixed-point layout")
+
+		format := FixedPointFormat{Signed: true, WordLength: 16, IntegerWordLength: 8}
+		assert.Equal(t, map[string][]float64{"/'g'/'c'": {-0.5, 1.5}}, readTestSamples(t, file, WithFixedPointFormat("/'g'/'c'", format)))
+	}
+}
@@ -0,0 +1,175 @@
+// Package testfile writes little-endian TDMS files for tests.
+//
+// The package does not import tdms, so that it can be used by the tests of the tdms package itself.
+package testfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Table of contents flags of test segments.
+const (
+	TocMetaData        = 1 << 1
+	TocNewObjList      = 1 << 2
+	TocRawData         = 1 << 3
+	TocInterleavedData = 1 << 5
+	TocDAQmxRawData    = 1 << 7
+)
+
+// Data types and raw data index types, as defined by the TDMS file format.
+const (
+	dataTypeI32                           = 0x03
+	dataTypeU32                           = 0x07
+	dataTypeSingleFloat                   = 0x09
+	dataTypeDoubleFloat                   = 0x0a
+	dataTypeString                        = 0x20
+	dataTypeBoolean                       = 0x21
+	dataTypeTimestamp                     = 0x44
+	rawDataIndexTypeSameAsPreviousSegment = 0x00000000
+	rawDataIndexTypeNoRawData             = 0xffffffff
+)
+
+// Segment describes a little-endian segment written by Write.
+type Segment struct {
+	ToC     uint32
+	Objects []Object
+	RawData []byte
+}
+
+// Object describes an object in the metadata of a test segment.
+type Object struct {
+	Path string
+	// RawDataIndex holds the raw data index, including its length or type. If nil, the object has no raw data.
+	RawDataIndex []byte
+	Properties   []Property
+}
+
+// Property is a property of a test object. The data type is derived from the Go type of the value, unless the value is a Value.
+type Property struct {
+	Name  string
+	Value any
+}
+
+// Value is a value with an explicit data type and encoding.
+type Value struct {
+	DataType uint32
+	Data     []byte
+}
+
+// Timestamp is a timestamp value, with the same layout as tdms.Timestamp.
+type Timestamp struct {
+	Seconds  int64
+	Fraction uint64
+}
+
+// RawDataIndexSameAsPreviousSegment is the raw data index of an object whose index is the same as in the previous segment.
+var RawDataIndexSameAsPreviousSegment = binary.LittleEndian.AppendUint32(nil, rawDataIndexTypeSameAsPreviousSegment)
+
+// RawDataIndex returns a raw data index of standard (non-DAQmx) raw data.
+func RawDataIndex[T ~uint32](dataType T, arrayDimension uint32, chunkSize uint64) []byte {
+	b := binary.LittleEndian.AppendUint32(nil, 20)
+	b = binary.LittleEndian.AppendUint32(b, uint32(dataType))
+	b = binary.LittleEndian.AppendUint32(b, arrayDimension)
+	return binary.LittleEndian.AppendUint64(b, chunkSize)
+}
+
+// WaveformProperties returns the waveform attributes of a channel.
+func WaveformProperties(startTime Timestamp, increment float64) []Property {
+	return []Property{
+		{"wf_start_time", startTime},
+		{"wf_start_offset", 0.0},
+		{"wf_increment", increment},
+	}
+}
+
+// RawData returns the little-endian encoding of the values.
+func RawData(values ...any) []byte {
+	var buffer bytes.Buffer
+	for _, value := range values {
+		_ = binary.Write(&buffer, binary.LittleEndian, value)
+	}
+	return buffer.Bytes()
+}
+
+// Write writes the segments to a TDMS file in a temporary directory, and returns its path.
+func Write(t testing.TB, segments ...Segment) string {
+	var b []byte
+	for _, segment := range segments {
+		var metadata []byte
+		if segment.ToC&TocMetaData != 0 {
+			metadata = binary.LittleEndian.AppendUint32(metadata, uint32(len(segment.Objects)))
+			for _, object := range segment.Objects {
+				metadata = appendString(metadata, object.Path)
+				if object.RawDataIndex == nil {
+					metadata = binary.LittleEndian.AppendUint32(metadata, rawDataIndexTypeNoRawData)
+				} else {
+					metadata = append(metadata, object.RawDataIndex...)
+				}
+				metadata = binary.LittleEndian.AppendUint32(metadata, uint32(len(object.Properties)))
+				for _, property := range object.Properties {
+					metadata = appendString(metadata, property.Name)
+					metadata = appendValue(t, metadata, property.Value)
+				}
+			}
+		}
+		b = append(b, "TDSm"...)
+		b = binary.LittleEndian.AppendUint32(b, segment.ToC)
+		b = binary.LittleEndian.AppendUint32(b, 4713)
+		b = binary.LittleEndian.AppendUint64(b, uint64(len(metadata)+len(segment.RawData)))
+		b = binary.LittleEndian.AppendUint64(b, uint64(len(metadata)))
+		b = append(b, metadata...)
+		b = append(b, segment.RawData...)
+	}
+	path := filepath.Join(t.TempDir(), "test.tdms")
+	err := os.WriteFile(path, b, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+func appendValue(t testing.TB, b []byte, value any) []byte {
+	switch v := value.(type) {
+	case Value:
+		b = binary.LittleEndian.AppendUint32(b, v.DataType)
+		return append(b, v.Data...)
+	case string:
+		b = binary.LittleEndian.AppendUint32(b, dataTypeString)
+		return appendString(b, v)
+	case bool:
+		b = binary.LittleEndian.AppendUint32(b, dataTypeBoolean)
+		if v {
+			return append(b, 1)
+		}
+		return append(b, 0)
+	case int32:
+		b = binary.LittleEndian.AppendUint32(b, dataTypeI32)
+		return binary.LittleEndian.AppendUint32(b, uint32(v))
+	case uint32:
+		b = binary.LittleEndian.AppendUint32(b, dataTypeU32)
+		return binary.LittleEndian.AppendUint32(b, v)
+	case float32:
+		b = binary.LittleEndian.AppendUint32(b, dataTypeSingleFloat)
+		return binary.LittleEndian.AppendUint32(b, math.Float32bits(v))
+	case float64:
+		b = binary.LittleEndian.AppendUint32(b, dataTypeDoubleFloat)
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+	case Timestamp:
+		b = binary.LittleEndian.AppendUint32(b, dataTypeTimestamp)
+		b = binary.LittleEndian.AppendUint64(b, v.Fraction)
+		return binary.LittleEndian.AppendUint64(b, uint64(v.Seconds))
+	default:
+		t.Fatalf("unsupported test value %T", value)
+		return nil
+	}
+}
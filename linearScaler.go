@@ -22,10 +22,16 @@ func NewLinearScaler(scaleId uint32, props map[string]any) (*LinearScaler, error
 		return nil, fmt.Errorf("Line_Input_Source not specified")
 	}
 	linearSlope, hasLinearSlope, err := utils.GetFloat64(props, "Linear_Slope")
+	if err != nil {
+		return nil, err
+	}
 	if !hasLinearSlope {
 		return nil, fmt.Errorf("Linear_Slope not specified")
 	}
 	linearYIntercept, hasLinearYIntercept, err := utils.GetFloat64(props, "Linear_Y_Intercept")
+	if err != nil {
+		return nil, err
+	}
 	if !hasLinearYIntercept {
 		return nil, fmt.Errorf("Linear_Y_Intercept not specified")
 	}
@@ -41,6 +47,28 @@ func (scaler *LinearScaler) ScaleId() uint32 {
 	return scaler.scaleId
 }
 
+func (scaler *LinearScaler) InputSource() uint {
+	return scaler.linearInputSource
+}
+
+func (scaler *LinearScaler) Slope() float64 {
+	return scaler.linearSlope
+}
+
+func (scaler *LinearScaler) YIntercept() float64 {
+	return scaler.linearYIntercept
+}
+
+func (scaler *LinearScaler) Info() ScalerInfo {
+	inputSource := scaler.linearInputSource
+	return ScalerInfo{
+		Type:         "Linear",
+		ScaleId:      scaler.scaleId,
+		InputSource:  &inputSource,
+		Coefficients: []float64{scaler.linearYIntercept, scaler.linearSlope},
+	}
+}
+
 func (scaler *LinearScaler) Scale(v any) (float64, error) {
 	n, err := utils.AsFloat64(v)
 	if err != nil {
@@ -33,14 +33,39 @@ func (m *MetaData) AddObject(object *Object) error {
 	return nil
 }
 
+func (m *MetaData) setObject(object *Object) error {
+	obj := m.objectMap[object.Path]
+	if obj == nil {
+		return m.AddObject(object)
+	}
+	obj.RawDataIndex = object.RawDataIndex
+	obj.Properties = object.Properties
+	obj.PropertyNames = object.PropertyNames
+	obj.PropertyTypes = object.PropertyTypes
+	return nil
+}
+
 func (m *MetaData) GetObjectByPath(path string) *Object {
 	return m.objectMap[path]
 }
 
 func ReadMetaData(r io.Reader, toc TableOfContents, previousSegment *Segment) (*MetaData, error) {
 	valueReader := toc.ValueReader()
-	// TODO handle NewObjList
 	metadata := NewMetaData()
+	if !toc.NewObjList() && (previousSegment != nil) && (previousSegment.MetaData != nil) {
+		// the object list of the previous segment is extended
+		for _, previousObject := range previousSegment.MetaData.Objects() {
+			err := metadata.AddObject(&Object{
+				Path:          previousObject.Path,
+				RawDataIndex:  previousObject.RawDataIndex,
+				Properties:    make(map[string]any),
+				PropertyTypes: make(map[string]DataType),
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
 	numberOfObjects, err := valueReader.ReadU32(r)
 	if err != nil {
 		return nil, err
@@ -92,8 +117,16 @@ func ReadMetaData(r io.Reader, toc TableOfContents, previousSegment *Segment) (*
 					Wrapf(err, "unsupported raw data index type (TODO)")
 			}
 		} else {
-			rawDataIndexBytes := make([]byte, rawDataIndexType)
-			_, err = r.Read(rawDataIndexBytes)
+			// the length of the raw data index includes the length field itself
+			if rawDataIndexType < 4 {
+				return nil, oops.
+					In("Metadata").
+					With("objectPath", object.Path).
+					With("rawDataIndexLength", rawDataIndexType).
+					Errorf("invalid raw data index length")
+			}
+			rawDataIndexBytes := make([]byte, rawDataIndexType-4)
+			_, err = io.ReadFull(r, rawDataIndexBytes)
 			if err != nil {
 				return nil, err
 			}
@@ -105,6 +138,7 @@ func ReadMetaData(r io.Reader, toc TableOfContents, previousSegment *Segment) (*
 		}
 
 		object.Properties = make(map[string]any)
+		object.PropertyTypes = make(map[string]DataType)
 		numberOfProperties, err := valueReader.ReadU32(r)
 		if err != nil {
 			return nil, err
@@ -114,11 +148,20 @@ func ReadMetaData(r io.Reader, toc TableOfContents, previousSegment *Segment) (*
 			if err != nil {
 				return nil, err
 			}
-			propertyValue, err := valueReader.ReadValue(r)
+			propertyDataType, err := valueReader.ReadU32(r)
 			if err != nil {
 				return nil, err
 			}
+			propertyValue, err := valueReader.ReadValueForDataType(r, DataType(propertyDataType))
+			if err != nil {
+				return nil, err
+			}
+			_, exists := object.Properties[propertyName]
+			if !exists {
+				object.PropertyNames = append(object.PropertyNames, propertyName)
+			}
 			object.Properties[propertyName] = propertyValue
+			object.PropertyTypes[propertyName] = DataType(propertyDataType)
 		}
 
 		scalers, err := GetScalers(object.Properties)
@@ -130,7 +173,7 @@ func ReadMetaData(r io.Reader, toc TableOfContents, previousSegment *Segment) (*
 			object.RawDataIndex.PopulateScalers(scalers)
 		}
 
-		err = metadata.AddObject(&object)
+		err = metadata.setObject(&object)
 		if err != nil {
 			return nil, err
 		}
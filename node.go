@@ -1,24 +1,36 @@
 package tdms
 
-import "github.com/egregors/sortedmap"
+import (
+	"github.com/ngyewch/tdms-go/utils"
+)
 
 type Node struct {
 	name       string
 	path       string
-	properties *sortedmap.SortedMap[map[string]any, string, any]
-	childMap   *sortedmap.SortedMap[map[string]*Node, string, *Node]
+	properties *utils.OrderedMap[string, any]
+	// propertyTypes holds the TDMS data types of the current property values.
+	propertyTypes map[string]DataType
+	childMap      *utils.OrderedMap[string, *Node]
+	parent        *Node
+	history       []PropertyChange
+}
+
+// PropertyChange records a property value set by a segment.
+type PropertyChange struct {
+	Name          string
+	Value         any
+	DataType      DataType
+	SegmentIndex  int
+	SegmentOffset int64
 }
 
 func NewNode(name string, path string) *Node {
 	return &Node{
-		name: name,
-		path: path,
-		properties: sortedmap.New[map[string]any, string, any](func(i, j sortedmap.KV[string, any]) bool {
-			return i.Key < j.Key
-		}),
-		childMap: sortedmap.New[map[string]*Node, string, *Node](func(i, j sortedmap.KV[string, *Node]) bool {
-			return i.Key < j.Key
-		}),
+		name:          name,
+		path:          path,
+		properties:    utils.NewOrderedMap[string, any](),
+		propertyTypes: make(map[string]DataType),
+		childMap:      utils.NewOrderedMap[string, *Node](),
 	}
 }
 
@@ -30,19 +42,92 @@ func (node *Node) Path() string {
 	return node.path
 }
 
-func (node *Node) Properties() *sortedmap.SortedMap[map[string]any, string, any] {
+// Parent returns the parent of the node, or nil for the root.
+func (node *Node) Parent() *Node {
+	return node.parent
+}
+
+// ObjectPath returns the parsed path of the node.
+func (node *Node) ObjectPath() ObjectPath {
+	switch {
+	case node.parent == nil:
+		return ObjectPath{}
+	case node.parent.parent == nil:
+		return ObjectPath{Group: node.name}
+	default:
+		return ObjectPath{Group: node.parent.name, Channel: node.name}
+	}
+}
+
+// Properties returns the properties of the node. Its iterators return the properties in the order they are defined in the file,
+// and its Sorted iterator returns them sorted by name.
+//
+// This is a breaking change: Properties used to return a *sortedmap.SortedMap, whose iterators return the properties sorted by name.
+// utils.OrderedMap has the same Insert, Get, Len, All, Keys, Values, Collect, CollectKeys and CollectValues methods, but not Delete or CollectAll.
+// Callers relying on the name order must use Sorted instead of All.
+func (node *Node) Properties() *utils.OrderedMap[string, any] {
 	return node.properties
 }
 
+// PropertyNames returns the names of the properties of the node, in the order they are defined in the file.
+func (node *Node) PropertyNames() []string {
+	return node.properties.CollectKeys()
+}
+
+// PropertyHistory returns every value set for the property, in segment order.
+func (node *Node) PropertyHistory(name string) []PropertyChange {
+	var changes []PropertyChange
+	for _, change := range node.history {
+		if change.Name == name {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// PropertyChanges returns every property value set for the node, in segment order.
+func (node *Node) PropertyChanges() []PropertyChange {
+	return node.history
+}
+
+// PropertyDataType returns the TDMS data type of the property, as defined in the file.
+func (node *Node) PropertyDataType(name string) (DataType, bool) {
+	dataType, exists := node.propertyTypes[name]
+	return dataType, exists
+}
+
+func (node *Node) setProperty(name string, value any, dataType DataType, segmentIndex int, segmentOffset int64) {
+	node.properties.Insert(name, value)
+	node.propertyTypes[name] = dataType
+	node.history = append(node.history, PropertyChange{
+		Name:          name,
+		Value:         value,
+		DataType:      dataType,
+		SegmentIndex:  segmentIndex,
+		SegmentOffset: segmentOffset,
+	})
+}
+
+// Children returns the children of the node, in the order they are defined in the file.
 func (node *Node) Children() []*Node {
 	return node.childMap.CollectValues()
 }
 
+// SortedChildren returns the children of the node, sorted by name.
+func (node *Node) SortedChildren() []*Node {
+	var children []*Node
+	for _, child := range node.childMap.Sorted() {
+		children = append(children, child)
+	}
+	return children
+}
+
 func (node *Node) GetChildByName(name string) *Node {
 	child, _ := node.childMap.Get(name)
 	return child
 }
 
 func (node *Node) AddChild(child *Node) {
+	child.parent = node
 	node.childMap.Insert(child.Name(), child)
 }
@@ -0,0 +1,245 @@
+package tdms
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ngyewch/tdms-go/utils"
+	"github.com/samber/oops"
+)
+
+// Property returns the value of the named property.
+func (node *Node) Property(name string) (any, bool) {
+	return node.properties.Get(name)
+}
+
+func (node *Node) GetInt(name string) (int, bool, error) {
+	return getNodeProperty(node, name, utils.AsInt)
+}
+
+func (node *Node) GetUint(name string) (uint, bool, error) {
+	return getNodeProperty(node, name, utils.AsUint)
+}
+
+func (node *Node) GetFloat64(name string) (float64, bool, error) {
+	return getNodeProperty(node, name, utils.AsFloat64)
+}
+
+func (node *Node) GetBool(name string) (bool, bool, error) {
+	return getNodeProperty(node, name, utils.AsBool)
+}
+
+func (node *Node) GetString(name string) (string, bool, error) {
+	return getNodeProperty(node, name, utils.AsString)
+}
+
+func (node *Node) GetTime(name string) (time.Time, bool, error) {
+	return getNodeProperty(node, name, utils.AsTime)
+}
+
+func (node *Node) GetTimestamp(name string) (Timestamp, bool, error) {
+	return getNodeProperty(node, name, AsTimestamp)
+}
+
+// GetIntOrDefault returns the named property, or the default value if the property does not exist.
+func (node *Node) GetIntOrDefault(name string, defaultValue int) (int, error) {
+	return getNodePropertyOrDefault(node, name, utils.AsInt, defaultValue)
+}
+
+// GetUintOrDefault returns the named property, or the default value if the property does not exist.
+func (node *Node) GetUintOrDefault(name string, defaultValue uint) (uint, error) {
+	return getNodePropertyOrDefault(node, name, utils.AsUint, defaultValue)
+}
+
+// GetFloat64OrDefault returns the named property, or the default value if the property does not exist.
+func (node *Node) GetFloat64OrDefault(name string, defaultValue float64) (float64, error) {
+	return getNodePropertyOrDefault(node, name, utils.AsFloat64, defaultValue)
+}
+
+// GetBoolOrDefault returns the named property, or the default value if the property does not exist.
+func (node *Node) GetBoolOrDefault(name string, defaultValue bool) (bool, error) {
+	return getNodePropertyOrDefault(node, name, utils.AsBool, defaultValue)
+}
+
+// GetStringOrDefault returns the named property, or the default value if the property does not exist.
+func (node *Node) GetStringOrDefault(name string, defaultValue string) (string, error) {
+	return getNodePropertyOrDefault(node, name, utils.AsString, defaultValue)
+}
+
+// GetTimeOrDefault returns the named property, or the default value if the property does not exist.
+func (node *Node) GetTimeOrDefault(name string, defaultValue time.Time) (time.Time, error) {
+	return getNodePropertyOrDefault(node, name, utils.AsTime, defaultValue)
+}
+
+// GetTimestampOrDefault returns the named property, or the default value if the property does not exist.
+func (node *Node) GetTimestampOrDefault(name string, defaultValue Timestamp) (Timestamp, error) {
+	return getNodePropertyOrDefault(node, name, AsTimestamp, defaultValue)
+}
+
+func getNodeProperty[T any](node *Node, name string, convert func(v any) (T, error)) (T, bool, error) {
+	var zero T
+	v, exists := node.properties.Get(name)
+	if !exists {
+		return zero, false, nil
+	}
+	t, err := convert(v)
+	if err != nil {
+		return zero, true, oops.
+			In("Node").
+			With("objectPath", node.path).
+			With("property", name).
+			Wrapf(err, "property %s of %s", name, node.path)
+	}
+	return t, true, nil
+}
+
+func getNodePropertyOrDefault[T any](node *Node, name string, convert func(v any) (T, error), defaultValue T) (T, error) {
+	t, exists, err := getNodeProperty(node, name, convert)
+	if err != nil {
+		return t, err
+	}
+	if !exists {
+		return defaultValue, nil
+	}
+	return t, nil
+}
+
+var (
+	timeType      = reflect.TypeFor[time.Time]()
+	timestampType = reflect.TypeFor[Timestamp]()
+)
+
+// UnmarshalProperties fills the fields of the struct pointed to by v from the properties of the node.
+// Fields are mapped to properties by the tdms struct tag, e.g. `tdms:"wf_increment"`; untagged fields are ignored.
+// The "required" tag option, e.g. `tdms:"operator,required"`, reports an error if the property does not exist.
+// Supported field types are bool, integers, floats, string, time.Time, Timestamp, any, and pointers to these.
+func UnmarshalProperties(node *Node, v any) error {
+	rv := reflect.ValueOf(v)
+	if (rv.Kind() != reflect.Pointer) || rv.IsNil() || (rv.Elem().Kind() != reflect.Struct) {
+		return oops.
+			In("UnmarshalProperties").
+			With("type", reflect.TypeOf(v)).
+			Errorf("pointer to struct expected")
+	}
+	structValue := rv.Elem()
+	structType := structValue.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag, ok := field.Tag.Lookup("tdms")
+		if !ok || (tag == "-") || !field.IsExported() {
+			continue
+		}
+		name, tagOptions, _ := strings.Cut(tag, ",")
+		required := false
+		for _, tagOption := range strings.Split(tagOptions, ",") {
+			if tagOption == "required" {
+				required = true
+			}
+		}
+		propertyValue, exists := node.properties.Get(name)
+		if !exists {
+			if required {
+				return oops.
+					In("UnmarshalProperties").
+					With("objectPath", node.path).
+					With("property", name).
+					Errorf("required property %s of %s not found", name, node.path)
+			}
+			continue
+		}
+		fieldValue := structValue.Field(i)
+		if fieldValue.Kind() == reflect.Pointer {
+			pointerValue := reflect.New(fieldValue.Type().Elem())
+			err := setPropertyValue(pointerValue.Elem(), propertyValue)
+			if err != nil {
+				return wrapUnmarshalError(node, name, field.Name, err)
+			}
+			fieldValue.Set(pointerValue)
+			continue
+		}
+		err := setPropertyValue(fieldValue, propertyValue)
+		if err != nil {
+			return wrapUnmarshalError(node, name, field.Name, err)
+		}
+	}
+	return nil
+}
+
+func wrapUnmarshalError(node *Node, name string, fieldName string, err error) error {
+	return oops.
+		In("UnmarshalProperties").
+		With("objectPath", node.path).
+		With("property", name).
+		With("field", fieldName).
+		Wrapf(err, "property %s of %s, field %s", name, node.path, fieldName)
+}
+
+func setPropertyValue(fieldValue reflect.Value, propertyValue any) error {
+	switch fieldValue.Type() {
+	case timeType:
+		t, err := utils.AsTime(propertyValue)
+		if err != nil {
+			return err
+		}
+		fieldValue.Set(reflect.ValueOf(t))
+		return nil
+	case timestampType:
+		ts, err := AsTimestamp(propertyValue)
+		if err != nil {
+			return err
+		}
+		fieldValue.Set(reflect.ValueOf(ts))
+		return nil
+	}
+	switch fieldValue.Kind() {
+	case reflect.Bool:
+		b, err := utils.AsBool(propertyValue)
+		if err != nil {
+			return err
+		}
+		fieldValue.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := utils.AsInt(propertyValue)
+		if err != nil {
+			return err
+		}
+		if fieldValue.OverflowInt(int64(n)) {
+			return oops.Errorf("value %d overflows %s", n, fieldValue.Type())
+		}
+		fieldValue.SetInt(int64(n))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := utils.AsUint(propertyValue)
+		if err != nil {
+			return err
+		}
+		if fieldValue.OverflowUint(uint64(n)) {
+			return oops.Errorf("value %d overflows %s", n, fieldValue.Type())
+		}
+		fieldValue.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		f, err := utils.AsFloat64(propertyValue)
+		if err != nil {
+			return err
+		}
+		if fieldValue.OverflowFloat(f) {
+			return oops.Errorf("value %g overflows %s", f, fieldValue.Type())
+		}
+		fieldValue.SetFloat(f)
+	case reflect.String:
+		s, err := utils.AsString(propertyValue)
+		if err != nil {
+			return err
+		}
+		fieldValue.SetString(s)
+	case reflect.Interface:
+		v := reflect.ValueOf(propertyValue)
+		if !v.Type().AssignableTo(fieldValue.Type()) {
+			return oops.Errorf("cannot assign %T to %s", propertyValue, fieldValue.Type())
+		}
+		fieldValue.Set(v)
+	default:
+		return oops.Errorf("unsupported field type %s", fieldValue.Type())
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package tdms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeProperties(t *testing.T) {
+	startTime := NewTimestamp(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	node := NewNode("ch1", "/'Group'/'ch1'")
+	node.setProperty("wf_increment", 0.001, DataTypeDoubleFloat, 0, 0)
+	node.setProperty("wf_samples", int32(1000), DataTypeI32, 0, 0)
+	node.setProperty("wf_start_time", startTime, DataTypeTimestamp, 0, 0)
+	node.setProperty("operator", "alice", DataTypeString, 0, 0)
+	node.setProperty("calibrated", true, DataTypeBoolean, 0, 0)
+	{
+		increment, exists, err := node.GetFloat64("wf_increment")
+		if assert.NoError(t, err) {
+			assert.True(t, exists)
+			assert.Equal(t, 0.001, increment)
+		}
+		gain, err := node.GetFloat64OrDefault("gain", 1)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1.0, gain)
+		}
+		_, _, err = node.GetInt("operator")
+		if assert.Error(t, err) {
+			assert.Equal(t, "property operator of /'Group'/'ch1': cannot convert alice to int", err.Error())
+		}
+	}
+	{
+		type channelProperties struct {
+			Increment float64   `tdms:"wf_increment"`
+			Samples   uint16    `tdms:"wf_samples,required"`
+			StartTime time.Time `tdms:"wf_start_time"`
+			Operator  *string   `tdms:"operator"`
+			Comment   *string   `tdms:"comment"`
+			Value     any       `tdms:"calibrated"`
+			Ignored   int
+		}
+		var properties channelProperties
+		err := UnmarshalProperties(node, &properties)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 0.001, properties.Increment)
+			assert.Equal(t, uint16(1000), properties.Samples)
+			assert.Equal(t, startTime.Time(), properties.StartTime)
+			if assert.NotNil(t, properties.Operator) {
+				assert.Equal(t, "alice", *properties.Operator)
+			}
+			assert.Nil(t, properties.Comment)
+			assert.Equal(t, true, properties.Value)
+		}
+	}
+	{
+		var properties struct {
+			Samples int8 `tdms:"wf_samples"`
+		}
+		err := UnmarshalProperties(node, &properties)
+		if assert.Error(t, err) {
+			assert.Equal(t, "property wf_samples of /'Group'/'ch1', field Samples: value 1000 overflows int8", err.Error())
+		}
+		var required struct {
+			Gain float64 `tdms:"gain,required"`
+		}
+		err = UnmarshalProperties(node, &required)
+		if assert.Error(t, err) {
+			assert.Equal(t, "required property gain of /'Group'/'ch1' not found", err.Error())
+		}
+		node.setProperty("range", 1e300, DataTypeDoubleFloat, 0, 0)
+		var singlePrecision struct {
+			Increment float32 `tdms:"wf_increment"`
+			Range     float32 `tdms:"range"`
+		}
+		err = UnmarshalProperties(node, &singlePrecision)
+		if assert.Error(t, err) {
+			assert.Equal(t, "property range of /'Group'/'ch1', field Range: value 1e+300 overflows float32", err.Error())
+		}
+		assert.Equal(t, float32(0.001), singlePrecision.Increment)
+		assert.Error(t, UnmarshalProperties(node, properties))
+	}
+}
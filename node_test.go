@@ -0,0 +1,53 @@
+package tdms
+
+import (
+	"testing"
+
+	"github.com/ngyewch/tdms-go/internal/testfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeOrder(t *testing.T) {
+	file := openTestFile(t,
+		testfile.Segment{
+			ToC: testfile.TocMetaData | testfile.TocNewObjList,
+			Objects: []testfile.Object{
+				{Path: "/"},
+				{Path: "/'b'"},
+				{Path: "/'b'/'c'", Properties: []testfile.Property{{Name: "z", Value: int32(1)}, {Name: "a", Value: int32(2)}}},
+				{Path: "/'a'"},
+			},
+		},
+		testfile.Segment{
+			ToC: testfile.TocMetaData,
+			Objects: []testfile.Object{
+				{Path: "/'b'/'c'", Properties: []testfile.Property{{Name: "m", Value: int32(3)}, {Name: "a", Value: int32(4)}}},
+			},
+		},
+	)
+	var names []string
+	for _, group := range file.Root().Children() {
+		names = append(names, group.Name())
+	}
+	assert.Equal(t, []string{"b", "a"}, names)
+	names = nil
+	for _, group := range file.Root().SortedChildren() {
+		names = append(names, group.Name())
+	}
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	channel := file.Node("/'b'/'c'")
+	if assert.NotNil(t, channel) {
+		// a property restated by a later segment keeps its position
+		assert.Equal(t, []string{"z", "a", "m"}, channel.PropertyNames())
+		assert.Equal(t, []string{"z", "a", "m"}, channel.Properties().CollectKeys())
+		names = nil
+		var values []any
+		for name, value := range channel.Properties().Sorted() {
+			names = append(names, name)
+			values = append(values, value)
+		}
+		assert.Equal(t, []string{"a", "m", "z"}, names)
+		assert.Equal(t, []any{int32(4), int32(3), int32(1)}, values)
+	}
+}
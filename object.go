@@ -4,4 +4,8 @@ type Object struct {
 	Path         string
 	RawDataIndex RawDataIndex
 	Properties   map[string]any
+	// PropertyNames holds the names of the properties, in the order they are defined in the segment.
+	PropertyNames []string
+	// PropertyTypes holds the TDMS data types of the properties.
+	PropertyTypes map[string]DataType
 }
@@ -0,0 +1,136 @@
+package tdms
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+
+	"github.com/ngyewch/tdms-go/utils"
+	"github.com/samber/oops"
+)
+
+// ChannelFilter reports whether a channel is selected.
+type ChannelFilter func(channel *Node) bool
+
+// NodeByObjectPath returns the node at the specified path, or nil if it does not exist.
+func (file *File) NodeByObjectPath(objectPath ObjectPath) *Node {
+	return file.Node(objectPath.String())
+}
+
+// Group returns the named group, or nil if it does not exist.
+func (file *File) Group(group string) *Node {
+	return file.NodeByObjectPath(ObjectPath{Group: group})
+}
+
+// Channel returns the named channel, or nil if it does not exist.
+func (file *File) Channel(group string, channel string) *Node {
+	return file.NodeByObjectPath(ObjectPath{Group: group, Channel: channel})
+}
+
+// Channels returns all channels of the file, in file order.
+func (file *File) Channels() []*Node {
+	var channels []*Node
+	if file.root == nil {
+		return channels
+	}
+	for _, group := range file.root.Children() {
+		channels = append(channels, group.Children()...)
+	}
+	return channels
+}
+
+// SelectChannels returns the channels, in file order, selected by all filters.
+func (file *File) SelectChannels(filters ...ChannelFilter) []*Node {
+	var channels []*Node
+	for _, channel := range file.Channels() {
+		selected := true
+		for _, filter := range filters {
+			if !filter(channel) {
+				selected = false
+				break
+			}
+		}
+		if selected {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// MatchGlob selects channels whose group and channel names match the glob patterns, as defined by path.Match. An empty pattern matches any name.
+func MatchGlob(groupPattern string, channelPattern string) (ChannelFilter, error) {
+	for _, pattern := range []string{groupPattern, channelPattern} {
+		_, err := path.Match(pattern, "")
+		if err != nil {
+			return nil, oops.
+				In("MatchGlob").
+				With("pattern", pattern).
+				Wrap(err)
+		}
+	}
+	match := func(pattern string, name string) bool {
+		if pattern == "" {
+			return true
+		}
+		matched, _ := path.Match(pattern, name)
+		return matched
+	}
+	return func(channel *Node) bool {
+		objectPath := channel.ObjectPath()
+		return match(groupPattern, objectPath.Group) && match(channelPattern, objectPath.Channel)
+	}, nil
+}
+
+// MatchRegexp selects channels whose group and channel names match the regular expressions. An empty expression matches any name.
+func MatchRegexp(groupExpr string, channelExpr string) (ChannelFilter, error) {
+	var regexps []*regexp.Regexp
+	for _, expr := range []string{groupExpr, channelExpr} {
+		if expr == "" {
+			regexps = append(regexps, nil)
+			continue
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, oops.
+				In("MatchRegexp").
+				With("expr", expr).
+				Wrap(err)
+		}
+		regexps = append(regexps, re)
+	}
+	match := func(re *regexp.Regexp, name string) bool {
+		return (re == nil) || re.MatchString(name)
+	}
+	return func(channel *Node) bool {
+		objectPath := channel.ObjectPath()
+		return match(regexps[0], objectPath.Group) && match(regexps[1], objectPath.Channel)
+	}, nil
+}
+
+// MatchProperty selects channels having the named property, with a value satisfying the predicate.
+func MatchProperty(name string, predicate func(value any) bool) ChannelFilter {
+	return func(channel *Node) bool {
+		value, exists := channel.Property(name)
+		return exists && predicate(value)
+	}
+}
+
+// PropertyEquals selects channels whose named property equals the value. Numeric values are compared as float64, and other values by their string representation.
+func PropertyEquals(name string, value any) ChannelFilter {
+	return MatchProperty(name, func(propertyValue any) bool {
+		return propertyValueEquals(propertyValue, value)
+	})
+}
+
+func propertyValueEquals(a any, b any) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	f1, err1 := utils.AsFloat64(a)
+	f2, err2 := utils.AsFloat64(b)
+	if (err1 == nil) && (err2 == nil) {
+		return f1 == f2
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
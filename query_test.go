@@ -0,0 +1,62 @@
+package tdms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectChannels(t *testing.T) {
+	file := &File{
+		nodeMap: make(map[string]*Node),
+	}
+	file.root = NewNode("", "/")
+	file.nodeMap["/"] = file.root
+	for _, groupName := range []string{"Accel", "Temp"} {
+		group := NewNode(groupName, ObjectPath{Group: groupName}.String())
+		file.root.AddChild(group)
+		file.nodeMap[group.Path()] = group
+		for _, channelName := range []string{"ch2", "ch10", "x'1"} {
+			channel := NewNode(channelName, ObjectPath{Group: groupName, Channel: channelName}.String())
+			group.AddChild(channel)
+			file.nodeMap[channel.Path()] = channel
+			if groupName == "Accel" {
+				channel.setProperty("unit_string", "g", DataTypeString, 0, 0)
+			}
+			channel.setProperty("gain", int32(len(channelName)), DataTypeI32, 0, 0)
+		}
+	}
+	paths := func(nodes []*Node) []string {
+		var result []string
+		for _, node := range nodes {
+			result = append(result, node.Path())
+		}
+		return result
+	}
+	{
+		channel := file.Channel("Accel", "x'1")
+		if assert.NotNil(t, channel) {
+			assert.Equal(t, "/'Accel'/'x''1'", channel.Path())
+			assert.Equal(t, file.Group("Accel"), channel.Parent())
+			assert.Equal(t, file.Root(), channel.Parent().Parent())
+			assert.Equal(t, ObjectPath{Group: "Accel", Channel: "x'1"}, channel.ObjectPath())
+		}
+		assert.Nil(t, file.Channel("Accel", "missing"))
+	}
+	{
+		filter, err := MatchGlob("", "ch*")
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"/'Accel'/'ch2'", "/'Accel'/'ch10'", "/'Temp'/'ch2'", "/'Temp'/'ch10'"}, paths(file.SelectChannels(filter)))
+			assert.Equal(t, []string{"/'Accel'/'ch2'", "/'Accel'/'ch10'"}, paths(file.SelectChannels(filter, PropertyEquals("unit_string", "g"))))
+		}
+		_, err = MatchGlob("[", "")
+		assert.Error(t, err)
+	}
+	{
+		filter, err := MatchRegexp("^T", `^ch\d$`)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"/'Temp'/'ch2'"}, paths(file.SelectChannels(filter)))
+		}
+		assert.Equal(t, []string{"/'Accel'/'ch10'", "/'Temp'/'ch10'"}, paths(file.SelectChannels(PropertyEquals("gain", 4.0))))
+	}
+}
@@ -60,6 +60,8 @@ func (index *DefaultRawDataIndex) CheckCompatibility(rawDataIndex RawDataIndex)
 	}
 }
 
+// ReadDefaultRawDataIndex reads a raw data index of standard (non-DAQmx) raw data, excluding its length. The reader must end with the raw data index,
+// since the fixed-point format descriptor of fixed-point channels is optional.
 func ReadDefaultRawDataIndex(r io.Reader, valueReader *ValueReader) (*DefaultRawDataIndex, error) {
 	var defaultRawDataIndex DefaultRawDataIndex
 	var err error
@@ -0,0 +1,96 @@
+package tdms
+
+type ReadOption func(options *readOptions)
+
+type readOptions struct {
+	raw           bool
+	hasScaleLimit bool
+	scaleLimit    uint32
+	calibration   *Calibration
+	from          *TimeBound
+	to            *TimeBound
+
+	hasSampleRange   bool
+	sampleStart      uint64
+	sampleEnd        uint64
+	decimationFactor uint64
+
+	fixedPointFormats map[string]FixedPointFormat
+}
+
+func newReadOptions(options ...ReadOption) *readOptions {
+	o := &readOptions{}
+	for _, option := range options {
+		option(o)
+	}
+	return o
+}
+
+// WithRawValues returns the values produced by the DAQmx format changing scaler, without applying any further scalers.
+func WithRawValues() ReadOption {
+	return func(options *readOptions) {
+		options.raw = true
+	}
+}
+
+// WithScaleLimit applies only the scalers with scale IDs up to and including the specified scale ID.
+func WithScaleLimit(scaleId uint32) ReadOption {
+	return func(options *readOptions) {
+		options.hasScaleLimit = true
+		options.scaleLimit = scaleId
+	}
+}
+
+// WithCalibration replaces or extends the scalers defined in the file with the scalers supplied by the calibration.
+func WithCalibration(calibration *Calibration) ReadOption {
+	return func(options *readOptions) {
+		options.calibration = calibration
+	}
+}
+
+// WithFixedPointFormat specifies the format of a fixed-point channel. Fixed-point channels cannot be read without it,
+// since the layout of the format stored in the file is not known.
+func WithFixedPointFormat(path string, format FixedPointFormat) ReadOption {
+	return func(options *readOptions) {
+		if options.fixedPointFormats == nil {
+			options.fixedPointFormats = make(map[string]FixedPointFormat)
+		}
+		options.fixedPointFormats[path] = format
+	}
+}
+
+func (options *readOptions) getFixedPointFormat(path string) *FixedPointFormat {
+	format, exists := options.fixedPointFormats[path]
+	if exists {
+		return &format
+	}
+	return nil
+}
+
+func (options *readOptions) getScalers(path string, scalers []Scaler) []Scaler {
+	if options.calibration == nil {
+		return scalers
+	}
+	return options.calibration.Apply(path, scalers)
+}
+
+func (options *readOptions) applyScalers(scalers []Scaler, v float64) (float64, error) {
+	if options.raw {
+		return v, nil
+	}
+	var err error
+	for _, scaler := range scalers {
+		if scaler == nil {
+			continue
+		}
+		if options.hasScaleLimit && (scaler.ScaleId() > options.scaleLimit) {
+			// scalers are not necessarily ordered by scale ID
+			continue
+		}
+		v, err = scaler.Scale(v)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,47 @@
+package tdms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyScalers(t *testing.T) {
+	newScaler := func(scaleId uint32, slope float64) Scaler {
+		scaler, err := NewLinearScaler(scaleId, map[string]any{
+			"Linear_Input_Source": uint32(0),
+			"Linear_Slope":        slope,
+			"Linear_Y_Intercept":  0.0,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return scaler
+	}
+	// scalers out of scale ID order
+	scalers := []Scaler{newScaler(2, 10), nil, newScaler(1, 2), newScaler(3, 100)}
+	{
+		v, err := newReadOptions().applyScalers(scalers, 1)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 2000.0, v)
+		}
+	}
+	{
+		v, err := newReadOptions(WithScaleLimit(1)).applyScalers(scalers, 1)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 2.0, v)
+		}
+	}
+	{
+		v, err := newReadOptions(WithScaleLimit(2)).applyScalers(scalers, 1)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 20.0, v)
+		}
+	}
+	{
+		v, err := newReadOptions(WithRawValues(), WithScaleLimit(2)).applyScalers(scalers, 1)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1.0, v)
+		}
+	}
+}
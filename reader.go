@@ -6,7 +6,6 @@ import (
 	"os"
 	"sync"
 
-	"github.com/ngyewch/tdms-go/utils"
 	"github.com/samber/oops"
 )
 
@@ -15,10 +14,11 @@ const (
 )
 
 type File struct {
-	r       io.ReadSeekCloser
-	root    *Node
-	nodeMap map[string]*Node
-	mutex   sync.Mutex
+	r               io.ReadSeekCloser
+	root            *Node
+	nodeMap         map[string]*Node
+	rawDataIndexMap map[string]RawDataIndex
+	mutex           sync.Mutex
 }
 
 func OpenFile(path string) (*File, error) {
@@ -27,8 +27,9 @@ func OpenFile(path string) (*File, error) {
 		return nil, err
 	}
 	tdmsFile := &File{
-		r:       f,
-		nodeMap: make(map[string]*Node),
+		r:               f,
+		nodeMap:         make(map[string]*Node),
+		rawDataIndexMap: make(map[string]RawDataIndex),
 	}
 	err = tdmsFile.readMetadata()
 	if err != nil {
@@ -49,6 +50,60 @@ func (file *File) Node(path string) *Node {
 	return file.nodeMap[path]
 }
 
+// ChannelDataType returns the data type of the raw data of the specified channel, as defined by the last segment containing raw data for the channel.
+// For DAQmx raw data, this is the data type of the first format changing scaler. It returns DataTypeVoid if the channel has no raw data.
+func (file *File) ChannelDataType(path string) DataType {
+	switch rawDataIndex := file.rawDataIndexMap[path].(type) {
+	case nil:
+		return DataTypeVoid
+	case *DAQmxRawDataIndex:
+		for _, scaler := range rawDataIndex.Scalers {
+			if formatChangingScaler, ok := scaler.(*DAQmxFormatChangingScaler); ok {
+				return formatChangingScaler.DataType()
+			}
+		}
+		return rawDataIndex.GetDataType()
+	default:
+		return rawDataIndex.GetDataType()
+	}
+}
+
+// ChannelArrayDimension returns the number of values of each sample of the specified channel, or 0 if the channel has no raw data.
+func (file *File) ChannelArrayDimension(path string) uint32 {
+	rawDataIndex := file.rawDataIndexMap[path]
+	if rawDataIndex == nil {
+		return 0
+	}
+	return rawDataIndex.GetArrayDimension()
+}
+
+// ChannelScalers returns the scaler chain of the specified channel, as defined by the last segment containing raw data for the channel.
+func (file *File) ChannelScalers(path string) ([]ScalerInfo, error) {
+	if file.Node(path) == nil {
+		return nil, fmt.Errorf("could not find object node")
+	}
+	var scalers []Scaler
+	switch rawDataIndex := file.rawDataIndexMap[path].(type) {
+	case nil:
+		return nil, nil
+	case *DAQmxRawDataIndex:
+		scalers = rawDataIndex.Scalers
+	default:
+		var err error
+		scalers, err = GetScalers(file.Node(path).Properties().Collect())
+		if err != nil {
+			return nil, err
+		}
+	}
+	var scalerInfos []ScalerInfo
+	for _, scaler := range scalers {
+		if scaler != nil {
+			scalerInfos = append(scalerInfos, scaler.Info())
+		}
+	}
+	return scalerInfos, nil
+}
+
 func (file *File) iterateSegments(handler func(segment *Segment) error) error {
 	_, err := file.r.Seek(0, io.SeekStart)
 	if err != nil {
@@ -102,19 +157,27 @@ func (file *File) readMetadata() error {
 	defer file.mutex.Unlock()
 
 	var root *Node
+	segmentIndex := -1
 	err := file.iterateSegments(func(segment *Segment) error {
+		segmentIndex++
+		if !segment.LeadIn.ToC.MetaData() {
+			return nil
+		}
 		for _, object := range segment.MetaData.objects {
 			objectPath, err := ObjectPathFromString(object.Path)
 			if err != nil {
 				return err
 			}
+			if object.RawDataIndex != nil {
+				file.rawDataIndexMap[object.Path] = object.RawDataIndex
+			}
 			if objectPath.IsRoot() {
 				if root == nil {
 					root = NewNode("", object.Path)
 					file.nodeMap[object.Path] = root
 				}
-				for name, value := range object.Properties {
-					root.Properties().Insert(name, value)
+				for _, name := range object.PropertyNames {
+					root.setProperty(name, object.Properties[name], object.PropertyTypes[name], segmentIndex, segment.Offset)
 				}
 				continue
 			}
@@ -131,8 +194,8 @@ func (file *File) readMetadata() error {
 					file.nodeMap[object.Path] = group
 					root.AddChild(group)
 				}
-				for name, value := range object.Properties {
-					group.Properties().Insert(name, value)
+				for _, name := range object.PropertyNames {
+					group.setProperty(name, object.Properties[name], object.PropertyTypes[name], segmentIndex, segment.Offset)
 				}
 			} else if objectPath.IsChannel() {
 				group := root.GetChildByName(objectPath.Group)
@@ -145,8 +208,8 @@ func (file *File) readMetadata() error {
 					file.nodeMap[object.Path] = channel
 					group.AddChild(channel)
 				}
-				for name, value := range object.Properties {
-					channel.Properties().Insert(name, value)
+				for _, name := range object.PropertyNames {
+					channel.setProperty(name, object.Properties[name], object.PropertyTypes[name], segmentIndex, segment.Offset)
 				}
 			}
 		}
@@ -161,6 +224,8 @@ func (file *File) readMetadata() error {
 	return nil
 }
 
+// Chunk holds the samples of a chunk of a segment. For DAQmx segments whose channels have different increments, each chunk holds the
+// channels of a single rate class, so a chunk does not necessarily hold every channel of its segment, see daqmxRateClass.
 type Chunk struct {
 	FileOffset int64
 	Channels   []ChannelData
@@ -170,9 +235,124 @@ type ChannelData struct {
 	Path               string
 	Node               *Node
 	WaveformAttributes *WaveformAttributes
-	Samples            []float64
+	// SampleOffset is the index of the first sample of the chunk within the channel.
+	SampleOffset uint64
+	// Shape is the shape of each sample, or nil for scalar samples.
+	Shape []int
+	// Samples holds the values of all samples of the chunk, in row-major order.
+	Samples []float64
+}
+
+// SampleSize returns the number of values of each sample.
+func (channelData ChannelData) SampleSize() int {
+	return sampleSize(channelData.Shape)
+}
+
+// SampleCount returns the number of samples of the chunk.
+func (channelData ChannelData) SampleCount() int {
+	return len(channelData.Samples) / channelData.SampleSize()
 }
 
+// Sample returns the values of the i-th sample of the chunk.
+func (channelData ChannelData) Sample(i int) []float64 {
+	n := channelData.SampleSize()
+	return channelData.Samples[i*n : (i+1)*n]
+}
+
+func sampleSize(shape []int) int {
+	n := 1
+	for _, dimension := range shape {
+		n *= dimension
+	}
+	return n
+}
+
+// sampleShape returns the shape of each sample of a channel with the specified array dimension.
+func sampleShape(arrayDimension uint32) []int {
+	if arrayDimension <= 1 {
+		return nil
+	}
+	return []int{int(arrayDimension)}
+}
+
+// GetChannelSampleCount returns the number of samples of the specified channel across all segments.
+func (file *File) GetChannelSampleCount(path string) (uint64, error) {
+	sampleCounts, err := file.GetChannelSampleCounts()
+	if err != nil {
+		return 0, err
+	}
+	return sampleCounts[path], nil
+}
+
+// GetChannelSampleCounts returns the number of samples of each channel across all segments.
+// If a time range, a sample range or decimation is specified, only the samples that are read are counted.
+func (file *File) GetChannelSampleCounts(options ...ReadOption) (map[string]uint64, error) {
+	readOptions := newReadOptions(options...)
+	if readOptions.hasTimeRange() {
+		return file.getTimeRangeSampleCounts(readOptions)
+	}
+	totalSampleCounts := make(map[string]uint64)
+	err := file.iterateSegments(func(segment *Segment) error {
+		sampleCounts, err := file.getSegmentSampleCounts(segment)
+		if err != nil {
+			return err
+		}
+		for path, sampleCount := range sampleCounts {
+			totalSampleCounts[path] += sampleCount
+		}
+		return nil
+	})
+	if err != nil {
+		if err != io.EOF {
+			return nil, err
+		}
+	}
+	if readOptions.hasSampleSelection() {
+		for path, sampleCount := range totalSampleCounts {
+			totalSampleCounts[path] = readOptions.selectedSampleCount(0, sampleCount)
+		}
+	}
+	return totalSampleCounts, nil
+}
+
+// getSegmentSampleCounts returns the number of samples of each channel in the segment.
+func (file *File) getSegmentSampleCounts(segment *Segment) (map[string]uint64, error) {
+	sampleCounts := make(map[string]uint64)
+	if !segment.LeadIn.ToC.RawData() {
+		return sampleCounts, nil
+	}
+	if segment.MetaData == nil {
+		return sampleCounts, nil
+	}
+	rawDataSize := segment.LeadIn.NextSegmentOffset - segment.LeadIn.RawDataOffset
+	if segment.LeadIn.ToC.DAQmxRawData() {
+		rateClasses, err := file.getDAQmxRateClasses(segment, newReadOptions())
+		if err != nil {
+			return nil, err
+		}
+		for _, rateClass := range rateClasses {
+			for _, channel := range rateClass.channels {
+				sampleCounts[channel.object.Path] = rateClass.sampleCount
+			}
+		}
+	} else {
+		channels, chunkByteSize, err := file.getDefaultChannels(segment, newReadOptions())
+		if err != nil {
+			return nil, err
+		}
+		if chunkByteSize == 0 {
+			return sampleCounts, nil
+		}
+		for _, channel := range channels {
+			sampleCounts[channel.object.Path] = (rawDataSize / chunkByteSize) * channel.rawDataIndex.GetChunkSize()
+		}
+	}
+	return sampleCounts, nil
+}
+
+// GetSampleCount returns the number of samples across all segments. For DAQmx segments containing channels of different rates, the
+// largest sample count of the segment's rate classes is used, so the result is not the sample count of every channel; use
+// GetChannelSampleCounts for the sample count of each channel.
 func (file *File) GetSampleCount() (uint64, error) {
 	var totalSampleCount uint64
 	err := file.iterateSegments(func(segment *Segment) error {
@@ -184,70 +364,21 @@ func (file *File) GetSampleCount() (uint64, error) {
 		}
 
 		if segment.LeadIn.ToC.DAQmxRawData() {
-			type Channel struct {
-				object             *Object
-				node               *Node
-				rawDataIndex       *DAQmxRawDataIndex
-				waveformAttributes *WaveformAttributes
-			}
-
-			var channels []Channel
-			var rawDataIndexes []*DAQmxRawDataIndex
-			for _, object := range segment.MetaData.Objects() {
-				if object.RawDataIndex != nil {
-					daqmxRawDataIndex := object.RawDataIndex.(*DAQmxRawDataIndex)
-					if daqmxRawDataIndex == nil {
-						return fmt.Errorf("DAQmx raw data index expected")
-					}
-					if len(daqmxRawDataIndex.Scalers) <= 0 {
-						return fmt.Errorf("no scalers defined")
-					}
-					daqmxFormatChangingScaler := daqmxRawDataIndex.Scalers[0].(*DAQmxFormatChangingScaler)
-					if daqmxFormatChangingScaler == nil {
-						return fmt.Errorf("DAQmx format changing scaler expected as first scaler")
-					}
-					node := file.Node(object.Path)
-					if node == nil {
-						return fmt.Errorf("could not find object node")
-					}
-					waveformAttributes, err := GetWaveformAttributes(node.Properties().Collect())
-					if err != nil {
-						return err
-					}
-					if len(channels) > 0 {
-						err := channels[0].rawDataIndex.CheckCompatibility(daqmxRawDataIndex)
-						if err != nil {
-							return err
-						}
-						if channels[0].waveformAttributes.Increment != waveformAttributes.Increment {
-							return fmt.Errorf("wf_increment not the same")
-						}
-					}
-					channels = append(channels, Channel{
-						object:             object,
-						node:               node,
-						rawDataIndex:       daqmxRawDataIndex,
-						waveformAttributes: waveformAttributes,
-					})
-					rawDataIndexes = append(rawDataIndexes, daqmxRawDataIndex)
-				}
+			rateClasses, err := file.getDAQmxRateClasses(segment, newReadOptions())
+			if err != nil {
+				return err
 			}
-			if len(channels) > 0 {
-				rawDataWidths := channels[0].rawDataIndex.RawDataWidths
-				buffers := make([][]byte, len(rawDataWidths))
-				var totalRawDataWidth uint32
-				for i, rawDataWidth := range rawDataWidths {
-					buffers[i] = make([]byte, rawDataWidth)
-					totalRawDataWidth += rawDataWidth
-				}
-
-				rawDataSize := segment.LeadIn.NextSegmentOffset - segment.LeadIn.RawDataOffset
-				sampleCount := int(rawDataSize / uint64(totalRawDataWidth))
-				totalSampleCount += uint64(sampleCount)
+			var sampleCount uint64
+			for _, rateClass := range rateClasses {
+				sampleCount = max(sampleCount, rateClass.sampleCount)
 			}
+			totalSampleCount += sampleCount
 		} else {
-			// TODO
-			return fmt.Errorf("not supported yet")
+			sampleCount, err := file.getDefaultSampleCount(segment)
+			if err != nil {
+				return err
+			}
+			totalSampleCount += sampleCount
 		}
 		return nil
 	})
@@ -259,141 +390,57 @@ func (file *File) GetSampleCount() (uint64, error) {
 	return totalSampleCount, nil
 }
 
-func (file *File) ReadData(chunkHandler func(chunk Chunk) error) error {
+// ReadData reads the raw data of all segments, and calls the chunk handler with each chunk read. The waveform attributes of each channel of a chunk
+// map the channel's sample indexes to the times of the samples in the chunk's segment, see File.TimeAxis. With a time range or a sample selection,
+// the raw data of segments without samples to read is not read.
+func (file *File) ReadData(chunkHandler func(chunk Chunk) error, options ...ReadOption) error {
+	readOptions := newReadOptions(options...)
+	timer := file.newSegmentTimer()
 	err := file.iterateSegments(func(segment *Segment) error {
+		timings, err := timer.next(segment)
+		if err != nil {
+			return err
+		}
 		if !segment.LeadIn.ToC.RawData() {
 			return nil
 		}
 		if segment.MetaData == nil {
 			return nil
 		}
+		if (readOptions.hasTimeRange() || readOptions.hasSampleSelection()) && !readOptions.selectsAnySample(timings) {
+			// the raw data of segments without samples to read is skipped
+			return nil
+		}
 
-		if segment.LeadIn.ToC.DAQmxRawData() {
-			type Channel struct {
-				object             *Object
-				node               *Node
-				rawDataIndex       *DAQmxRawDataIndex
-				waveformAttributes *WaveformAttributes
-			}
-
-			var channels []Channel
-			var rawDataIndexes []*DAQmxRawDataIndex
-			for _, object := range segment.MetaData.Objects() {
-				if object.RawDataIndex != nil {
-					daqmxRawDataIndex := object.RawDataIndex.(*DAQmxRawDataIndex)
-					if daqmxRawDataIndex == nil {
-						return fmt.Errorf("DAQmx raw data index expected")
-					}
-					if len(daqmxRawDataIndex.Scalers) <= 0 {
-						return fmt.Errorf("no scalers defined")
-					}
-					daqmxFormatChangingScaler := daqmxRawDataIndex.Scalers[0].(*DAQmxFormatChangingScaler)
-					if daqmxFormatChangingScaler == nil {
-						return fmt.Errorf("DAQmx format changing scaler expected as first scaler")
-					}
-					node := file.Node(object.Path)
-					if node == nil {
-						return fmt.Errorf("could not find object node")
-					}
-					waveformAttributes, err := GetWaveformAttributes(node.Properties().Collect())
-					if err != nil {
-						return err
-					}
-					if len(channels) > 0 {
-						err := channels[0].rawDataIndex.CheckCompatibility(daqmxRawDataIndex)
-						if err != nil {
-							return err
-						}
-						if channels[0].waveformAttributes.Increment != waveformAttributes.Increment {
-							return fmt.Errorf("wf_increment not the same")
-						}
-					}
-					channels = append(channels, Channel{
-						object:             object,
-						node:               node,
-						rawDataIndex:       daqmxRawDataIndex,
-						waveformAttributes: waveformAttributes,
-					})
-					rawDataIndexes = append(rawDataIndexes, daqmxRawDataIndex)
+		sampleOffsets := make(map[string]uint64)
+		handleChunk := func(chunk Chunk) error {
+			for channelNo, channel := range chunk.Channels {
+				timing, exists := timings[channel.Path]
+				if !exists {
+					continue
 				}
+				chunk.Channels[channelNo].SampleOffset = timing.sampleIndex + sampleOffsets[channel.Path]
+				chunk.Channels[channelNo].WaveformAttributes = timing.axisAttributes
+				sampleOffsets[channel.Path] += uint64(channel.SampleCount())
 			}
-			if len(channels) > 0 {
-				chunkSize := channels[0].rawDataIndex.GetChunkSize()
-				rawDataWidths := channels[0].rawDataIndex.RawDataWidths
-				buffers := make([][]byte, len(rawDataWidths))
-				var totalRawDataWidth uint32
-				for i, rawDataWidth := range rawDataWidths {
-					buffers[i] = make([]byte, rawDataWidth)
-					totalRawDataWidth += rawDataWidth
+			if readOptions.hasTimeRange() {
+				chunk = readOptions.selectTimeRange(chunk, timings)
+				if len(chunk.Channels) == 0 {
+					return nil
 				}
-
-				valueReader := segment.LeadIn.ToC.ValueReader()
-				rawDataSize := segment.LeadIn.NextSegmentOffset - segment.LeadIn.RawDataOffset
-				sampleCount := int(rawDataSize / uint64(totalRawDataWidth))
-
-				var chunk Chunk
-				for _, channel := range channels {
-					chunk.Channels = append(chunk.Channels, ChannelData{
-						Path:               channel.object.Path,
-						Node:               channel.node,
-						WaveformAttributes: channel.waveformAttributes,
-						Samples:            make([]float64, chunkSize),
-					})
-				}
-
-				for i := 0; i < sampleCount; i += int(chunkSize) {
-					chunkSampleCount := chunkSize
-					if i+int(chunkSize) > sampleCount {
-						chunkSampleCount = uint64(sampleCount - i)
-						for _, chunkChannel := range chunk.Channels {
-							chunkChannel.Samples = make([]float64, chunkSampleCount)
-						}
-					}
-					for j := 0; j < int(chunkSampleCount); j++ {
-						for bufferNo := 0; bufferNo < len(buffers); bufferNo++ {
-							_, err := file.r.Read(buffers[bufferNo])
-							if err != nil {
-								return err
-							}
-						}
-						for channelNo, channel := range channels {
-							firstScaler := channel.rawDataIndex.Scalers[0].(*DAQmxFormatChangingScaler)
-							if firstScaler == nil {
-								return fmt.Errorf("DAQmx format changing scaler expected as first scaler")
-							}
-							v0, err := firstScaler.ReadFromBuffer(valueReader, buffers)
-							if err != nil {
-								return err
-							}
-							v, err := utils.AsFloat64(v0)
-							if err != nil {
-								return err
-							}
-							for _, scaler := range channel.rawDataIndex.Scalers[1:] {
-								v, err = scaler.Scale(v)
-								if err != nil {
-									return err
-								}
-							}
-							chunk.Channels[channelNo].Samples[j] = v
-						}
-					}
-					fileOffset, err := file.r.Seek(0, io.SeekCurrent)
-					if err != nil {
-						return err
-					}
-					chunk.FileOffset = fileOffset
-					err = chunkHandler(chunk)
-					if err != nil {
-						return err
-					}
+			}
+			if readOptions.hasSampleSelection() {
+				chunk = readOptions.selectSamples(chunk)
+				if len(chunk.Channels) == 0 {
+					return nil
 				}
 			}
-		} else {
-			// TODO
-			return fmt.Errorf("not supported yet")
+			return chunkHandler(chunk)
 		}
-		return nil
+		if segment.LeadIn.ToC.DAQmxRawData() {
+			return file.readDAQmxData(segment, readOptions, handleChunk)
+		}
+		return file.readDefaultData(segment, readOptions, handleChunk)
 	})
 	if err != nil {
 		if err != io.EOF {
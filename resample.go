@@ -0,0 +1,491 @@
+package tdms
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/samber/oops"
+)
+
+type ResampleMethod int
+
+const (
+	// ResampleZeroOrderHold holds the value of the last sample at or before each output time, including after the last sample.
+	ResampleZeroOrderHold ResampleMethod = iota
+	// ResampleLinear interpolates linearly between the samples surrounding each output time.
+	ResampleLinear
+	// ResamplePolyphase interpolates using a bank of windowed-sinc filters, low-pass filtering when downsampling.
+	ResamplePolyphase
+)
+
+const (
+	polyphaseFilterPhases        = 256
+	polyphaseFilterZeroCrossings = 16
+	// polyphaseFilterMaxHalfWidth caps the number of taps on each side of the filters. When downsampling by a factor above
+	// polyphaseFilterMaxHalfWidth/polyphaseFilterZeroCrossings, the filters have fewer zero crossings, trading stop-band attenuation for bounded cost.
+	polyphaseFilterMaxHalfWidth = 1024
+
+	// resampleWindowLength is the maximum number of resampled samples of a channel in each chunk passed to the chunk handler of ReadResampled.
+	resampleWindowLength = 65536
+)
+
+func (method ResampleMethod) String() string {
+	switch method {
+	case ResampleZeroOrderHold:
+		return "zoh"
+	case ResampleLinear:
+		return "linear"
+	case ResamplePolyphase:
+		return "polyphase"
+	default:
+		return fmt.Sprintf("ResampleMethod(%d)", int(method))
+	}
+}
+
+func ParseResampleMethod(s string) (ResampleMethod, error) {
+	switch strings.ToLower(s) {
+	case "zoh", "zero-order-hold":
+		return ResampleZeroOrderHold, nil
+	case "linear":
+		return ResampleLinear, nil
+	case "polyphase":
+		return ResamplePolyphase, nil
+	default:
+		return 0, oops.
+			In("ParseResampleMethod").
+			With("method", s).
+			Errorf("unknown resample method")
+	}
+}
+
+type ResampledChannel struct {
+	Path    string
+	Node    *Node
+	Samples []float64
+}
+
+// ResampledData holds channels resampled onto a common time axis.
+type ResampledData struct {
+	TimeAxis *TimeAxis
+	Channels []ResampledChannel
+}
+
+// Resample resamples the samples of a channel, described by the source time axis, onto the target time axis.
+// Output samples before the first sample of the source are NaN. Output samples after the last sample of the source hold the value of
+// the last sample with ResampleZeroOrderHold, and are NaN otherwise.
+func Resample(source *TimeAxis, samples []float64, target *TimeAxis, method ResampleMethod) ([]float64, error) {
+	if source.Increment <= 0 {
+		return nil, oops.
+			In("Resample").
+			With("increment", source.Increment).
+			Errorf("invalid source increment")
+	}
+	resampler, err := newChannelResampler(target, method)
+	if err != nil {
+		return nil, err
+	}
+	offset := source.StartTime.SubSeconds(target.StartTime)
+	times := make([]float64, len(samples))
+	for i := range times {
+		times[i] = offset + source.RelativeTime(uint64(i))
+	}
+	output := make([]float64, 0, target.Length)
+	handleOutput := func(firstSample uint64, samples []float64) error {
+		output = append(output, samples...)
+		return nil
+	}
+	err = resampler.resample(times, samples, source.Increment, false, handleOutput)
+	if err != nil {
+		return nil, err
+	}
+	err = resampler.resample(nil, nil, source.Increment, true, handleOutput)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// ResampleTimeAxis returns the common time axis, at the specified rate, onto which File.Resample and File.ReadResampled resample the
+// specified channels. If rate is 0, the rate of the fastest channel is used. The axis spans from the earliest start to the latest end of
+// the samples of the channels that are read with the read options. It is derived from the timing of each segment, without reading any samples.
+func (file *File) ResampleTimeAxis(paths []string, rate float64, options ...ReadOption) (*TimeAxis, error) {
+	readOptions := newReadOptions(options...)
+	factor := max(1, readOptions.decimationFactor)
+	timingMap, err := file.getSegmentTimings()
+	if err != nil {
+		return nil, err
+	}
+	var start, end Timestamp
+	var maxRate float64
+	found := false
+	for _, path := range paths {
+		for _, timing := range timingMap[path] {
+			increment := timing.waveformAttributes.Increment
+			if increment <= 0 {
+				return nil, oops.
+					In("ResampleTimeAxis").
+					With("path", path).
+					With("increment", increment).
+					Errorf("invalid increment")
+			}
+			first, last, ok := readOptions.selectedSampleSpan(timing)
+			if !ok {
+				continue
+			}
+			firstTime := timing.sampleTime(first)
+			lastTime := timing.sampleTime(last)
+			if !found || firstTime.Before(start) {
+				start = firstTime
+			}
+			if !found || lastTime.After(end) {
+				end = lastTime
+			}
+			maxRate = max(maxRate, 1/(increment*float64(factor)))
+			found = true
+		}
+	}
+	if !found {
+		return &TimeAxis{
+			Increment: 1 / max(rate, 1),
+		}, nil
+	}
+	if rate <= 0 {
+		rate = maxRate
+	}
+	return &TimeAxis{
+		StartTime: start,
+		Increment: 1 / rate,
+		Length:    uint64(math.Floor(end.SubSeconds(start)*rate+1e-9)) + 1,
+	}, nil
+}
+
+// ReadResampled reads the specified channels and resamples them onto the target time axis, see ResampleTimeAxis. The resampled samples
+// are passed to the chunk handler as they become available, each chunk holding a window of at most resampleWindowLength samples of a
+// single channel, so only the source samples needed for the next window of each channel are held in memory.
+// The times of the source samples are derived from the timing of each segment, honouring restarts of wf_start_time.
+func (file *File) ReadResampled(chunkHandler func(chunk Chunk) error, paths []string, target *TimeAxis, method ResampleMethod, options ...ReadOption) error {
+	resamplers := make(map[string]*channelResampler)
+	for _, path := range paths {
+		node := file.Node(path)
+		if node == nil {
+			return oops.
+				In("ReadResampled").
+				With("path", path).
+				Errorf("could not find object node")
+		}
+		waveformAttributes, err := GetWaveformAttributes(node.Properties().Collect())
+		if err != nil {
+			return err
+		}
+		waveformAttributes.StartTime = target.StartTime
+		waveformAttributes.StartOffset = target.StartOffset
+		waveformAttributes.Increment = target.Increment
+		resampler, err := newChannelResampler(target, method)
+		if err != nil {
+			return err
+		}
+		resampler.path = path
+		resampler.node = node
+		resampler.waveformAttributes = waveformAttributes
+		resamplers[path] = resampler
+	}
+	handleOutput := func(resampler *channelResampler) func(firstSample uint64, samples []float64) error {
+		return func(firstSample uint64, samples []float64) error {
+			return chunkHandler(Chunk{
+				Channels: []ChannelData{
+					{
+						Path:               resampler.path,
+						Node:               resampler.node,
+						WaveformAttributes: resampler.waveformAttributes,
+						SampleOffset:       target.FirstSample + firstSample,
+						Samples:            samples,
+					},
+				},
+			})
+		}
+	}
+	err := file.ReadData(func(chunk Chunk) error {
+		for _, channel := range chunk.Channels {
+			resampler, exists := resamplers[channel.Path]
+			if !exists {
+				continue
+			}
+			if channel.Shape != nil {
+				return oops.
+					In("ReadResampled").
+					With("path", channel.Path).
+					Errorf("resampling of array channels not supported")
+			}
+			timeAxis := channel.TimeAxis()
+			if timeAxis.Increment <= 0 {
+				return oops.
+					In("ReadResampled").
+					With("path", channel.Path).
+					With("increment", timeAxis.Increment).
+					Errorf("invalid increment")
+			}
+			offset := timeAxis.StartTime.SubSeconds(target.StartTime)
+			times := make([]float64, len(channel.Samples))
+			for i := range times {
+				times[i] = offset + timeAxis.RelativeTime(uint64(i))
+			}
+			err := resampler.resample(times, channel.Samples, timeAxis.Increment, false, handleOutput(resampler))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, options...)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		resampler := resamplers[path]
+		err = resampler.resample(nil, nil, resampler.increment, true, handleOutput(resampler))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Resample reads the specified channels and resamples them onto a common time axis at the specified rate, see ResampleTimeAxis.
+// The resampled samples of all channels are held in memory; use ReadResampled to handle them a window at a time.
+func (file *File) Resample(paths []string, rate float64, method ResampleMethod, options ...ReadOption) (*ResampledData, error) {
+	target, err := file.ResampleTimeAxis(paths, rate, options...)
+	if err != nil {
+		return nil, err
+	}
+	resampledData := &ResampledData{
+		TimeAxis: target,
+	}
+	indexMap := make(map[string]int)
+	for i, path := range paths {
+		indexMap[path] = i
+		resampledData.Channels = append(resampledData.Channels, ResampledChannel{
+			Path:    path,
+			Node:    file.Node(path),
+			Samples: make([]float64, 0, target.Length),
+		})
+	}
+	err = file.ReadResampled(func(chunk Chunk) error {
+		for _, channel := range chunk.Channels {
+			resampledChannel := &resampledData.Channels[indexMap[channel.Path]]
+			resampledChannel.Samples = append(resampledChannel.Samples, channel.Samples...)
+		}
+		return nil
+	}, paths, target, method, options...)
+	if err != nil {
+		return nil, err
+	}
+	return resampledData, nil
+}
+
+// channelResampler resamples the samples of a channel onto a target time axis as they are read, holding only the source samples
+// that are needed for the output samples that have not yet been computed.
+type channelResampler struct {
+	path               string
+	node               *Node
+	waveformAttributes *WaveformAttributes
+	target             *TimeAxis
+	method             ResampleMethod
+	filter             *polyphaseFilter
+	// reachAhead and reachBehind are the numbers of source samples after and before the position of an output sample that are needed to compute it.
+	reachAhead  int
+	reachBehind int
+	// times holds the times of the buffered source samples, in seconds relative to the start time of the target axis.
+	times   []float64
+	samples []float64
+	// increment is the increment of the last source samples.
+	increment float64
+	// cursor is the index of the last buffered source sample at or before the time of the next output sample.
+	cursor int
+	// next is the index of the next output sample.
+	next   uint64
+	output []float64
+}
+
+func newChannelResampler(target *TimeAxis, method ResampleMethod) (*channelResampler, error) {
+	resampler := &channelResampler{
+		target:     target,
+		method:     method,
+		reachAhead: 1,
+	}
+	switch method {
+	case ResampleZeroOrderHold, ResampleLinear:
+	case ResamplePolyphase:
+		resampler.setFilter(newPolyphaseFilter(1))
+	default:
+		return nil, oops.
+			In("Resample").
+			With("method", method.String()).
+			Errorf("unsupported resample method")
+	}
+	return resampler, nil
+}
+
+func (resampler *channelResampler) setFilter(filter *polyphaseFilter) {
+	resampler.filter = filter
+	resampler.reachAhead = filter.halfWidth
+	resampler.reachBehind = filter.halfWidth - 1
+}
+
+// resample appends the source samples, with the specified times and increment, and passes the output samples that can be computed
+// to the output handler, a window at a time. If final is true, there are no more source samples, and all remaining output samples are computed.
+func (resampler *channelResampler) resample(times []float64, samples []float64, increment float64, final bool, handleOutput func(firstSample uint64, samples []float64) error) error {
+	if increment > 0 {
+		resampler.increment = increment
+		// low-pass filter when downsampling
+		cutoff := min(1, increment/resampler.target.Increment)
+		if (resampler.filter != nil) && (cutoff != resampler.filter.cutoff) {
+			resampler.setFilter(newPolyphaseFilter(cutoff))
+		}
+	}
+	resampler.times = append(resampler.times, times...)
+	resampler.samples = append(resampler.samples, samples...)
+
+	for resampler.next < resampler.target.Length {
+		v, ok := resampler.compute(resampler.target.RelativeTime(resampler.next), final)
+		if !ok {
+			break
+		}
+		if resampler.output == nil {
+			resampler.output = make([]float64, 0, min(resampleWindowLength, resampler.target.Length-resampler.next))
+		}
+		resampler.output = append(resampler.output, v)
+		resampler.next++
+		if len(resampler.output) >= resampleWindowLength {
+			err := resampler.flush(handleOutput)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	err := resampler.flush(handleOutput)
+	if err != nil {
+		return err
+	}
+
+	// drop the source samples that are no longer needed
+	drop := resampler.cursor - resampler.reachBehind
+	if drop > 0 {
+		resampler.times = append(resampler.times[:0], resampler.times[drop:]...)
+		resampler.samples = append(resampler.samples[:0], resampler.samples[drop:]...)
+		resampler.cursor -= drop
+	}
+	return nil
+}
+
+func (resampler *channelResampler) flush(handleOutput func(firstSample uint64, samples []float64) error) error {
+	if len(resampler.output) == 0 {
+		return nil
+	}
+	output := resampler.output
+	resampler.output = nil
+	return handleOutput(resampler.next-uint64(len(output)), output)
+}
+
+// compute returns the value of the output sample at time t, relative to the start time of the target axis, or false if more source
+// samples are needed to compute it.
+func (resampler *channelResampler) compute(t float64, final bool) (float64, bool) {
+	times := resampler.times
+	if len(times) == 0 {
+		return math.NaN(), final
+	}
+	if t < times[0] {
+		// before the first sample, as the samples before the cursor are kept
+		return math.NaN(), true
+	}
+	for (resampler.cursor+1 < len(times)) && (times[resampler.cursor+1] <= t) {
+		resampler.cursor++
+	}
+	n := resampler.cursor
+	if n+resampler.reachAhead >= len(times) && !final {
+		return 0, false
+	}
+	samples := resampler.samples
+	if n == len(times)-1 {
+		// at or after the last sample, allowing for rounding errors of the sample times
+		if (resampler.method == ResampleZeroOrderHold) || (t-times[n] <= resampler.increment*1e-6) {
+			return samples[n], true
+		}
+		return math.NaN(), true
+	}
+	var frac float64
+	if times[n+1] > times[n] {
+		frac = (t - times[n]) / (times[n+1] - times[n])
+	}
+	switch resampler.method {
+	case ResampleZeroOrderHold:
+		return samples[n], true
+	case ResampleLinear:
+		return samples[n] + (samples[n+1]-samples[n])*frac, true
+	default:
+		return resampler.filter.interpolate(samples, float64(n)+frac), true
+	}
+}
+
+// polyphaseFilter is a bank of windowed-sinc filters, one for each fractional sample phase.
+type polyphaseFilter struct {
+	cutoff       float64
+	halfWidth    int
+	coefficients [][]float64
+}
+
+// newPolyphaseFilter creates a polyphase filter with the specified cutoff, relative to the source Nyquist frequency.
+func newPolyphaseFilter(cutoff float64) *polyphaseFilter {
+	halfWidth := min(int(math.Ceil(polyphaseFilterZeroCrossings/cutoff)), polyphaseFilterMaxHalfWidth)
+	filter := &polyphaseFilter{
+		cutoff:       cutoff,
+		halfWidth:    halfWidth,
+		coefficients: make([][]float64, polyphaseFilterPhases),
+	}
+	for phase := range filter.coefficients {
+		offset := float64(phase) / polyphaseFilterPhases
+		taps := make([]float64, 2*halfWidth)
+		var sum float64
+		for k := range taps {
+			// distance between the tap's sample and the interpolated position
+			d := float64(k-halfWidth+1) - offset
+			taps[k] = cutoff * sinc(cutoff*d) * blackman(d/float64(halfWidth))
+			sum += taps[k]
+		}
+		for k := range taps {
+			taps[k] /= sum
+		}
+		filter.coefficients[phase] = taps
+	}
+	return filter
+}
+
+// interpolate returns the value at fractional sample position x, replicating the edge samples beyond the bounds.
+func (filter *polyphaseFilter) interpolate(samples []float64, x float64) float64 {
+	n := int(math.Floor(x))
+	phase := int(math.Round((x - float64(n)) * polyphaseFilterPhases))
+	if phase == polyphaseFilterPhases {
+		n++
+		phase = 0
+	}
+	var v float64
+	for k, coefficient := range filter.coefficients[phase] {
+		j := min(max(n+k-filter.halfWidth+1, 0), len(samples)-1)
+		v += samples[j] * coefficient
+	}
+	return v
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// blackman returns the Blackman window at x, where x ranges from -1 to 1.
+func blackman(x float64) float64 {
+	if math.Abs(x) >= 1 {
+		return 0
+	}
+	return 0.42 + 0.5*math.Cos(math.Pi*x) + 0.08*math.Cos(2*math.Pi*x)
+}
@@ -0,0 +1,179 @@
+package tdms
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ngyewch/tdms-go/internal/testfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResample(t *testing.T) {
+	startTime := NewTimestamp(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	source := &TimeAxis{
+		StartTime: startTime,
+		Increment: 1,
+		Length:    4,
+	}
+	samples := []float64{0, 10, 20, 30}
+	{
+		target := &TimeAxis{
+			StartTime: startTime.AddSeconds(-0.5),
+			Increment: 0.5,
+			Length:    8,
+		}
+		output, err := Resample(source, samples, target, ResampleZeroOrderHold)
+		if assert.NoError(t, err) {
+			assert.True(t, math.IsNaN(output[0]))
+			assert.Equal(t, []float64{0, 0, 10, 10, 20, 20, 30}, output[1:])
+		}
+		output, err = Resample(source, samples, target, ResampleLinear)
+		if assert.NoError(t, err) {
+			assert.True(t, math.IsNaN(output[0]))
+			assert.Equal(t, []float64{0, 5, 10, 15, 20, 25, 30}, output[1:])
+		}
+	}
+	{
+		constant := []float64{1, 1, 1, 1, 1, 1, 1, 1}
+		target := &TimeAxis{
+			StartTime: startTime,
+			Increment: 0.25,
+			Length:    29,
+		}
+		output, err := Resample(&TimeAxis{StartTime: startTime, Increment: 1, Length: 8}, constant, target, ResamplePolyphase)
+		if assert.NoError(t, err) {
+			for _, v := range output {
+				assert.InDelta(t, 1, v, 1e-9)
+			}
+		}
+	}
+	{
+		// after the last sample, zero-order hold holds the last value
+		target := &TimeAxis{
+			StartTime: startTime,
+			Increment: 1,
+			Length:    6,
+		}
+		output, err := Resample(source, samples, target, ResampleZeroOrderHold)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []float64{0, 10, 20, 30, 30, 30}, output)
+		}
+		output, err = Resample(source, samples, target, ResampleLinear)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []float64{0, 10, 20, 30}, output[:4])
+			assert.True(t, math.IsNaN(output[4]))
+			assert.True(t, math.IsNaN(output[5]))
+		}
+	}
+	{
+		filter := newPolyphaseFilter(1.0 / 4096)
+		assert.Equal(t, polyphaseFilterMaxHalfWidth, filter.halfWidth)
+		assert.Equal(t, polyphaseFilterZeroCrossings*4, newPolyphaseFilter(0.25).halfWidth)
+	}
+	{
+		method, err := ParseResampleMethod("ZOH")
+		if assert.NoError(t, err) {
+			assert.Equal(t, ResampleZeroOrderHold, method)
+		}
+		_, err = ParseResampleMethod("cubic")
+		assert.Error(t, err)
+	}
+}
+
+func TestFileResample(t *testing.T) {
+	startTime := NewTimestamp(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	root := testfile.Object{Path: "/"}
+	group := testfile.Object{Path: "/'g'"}
+	// channel a restarts at +10s in the second segment, channel b only has samples in the third segment
+	file := openTestFile(t,
+		testfile.Segment{
+			ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData,
+			Objects: []testfile.Object{
+				root,
+				group,
+				{Path: "/'g'/'a'", RawDataIndex: testfile.RawDataIndex(DataTypeDoubleFloat, 1, 3), Properties: testfile.WaveformProperties(testfile.Timestamp(startTime), 1)},
+			},
+			RawData: testfile.RawData([]float64{0, 1, 2}),
+		},
+		testfile.Segment{
+			ToC: testfile.TocMetaData | testfile.TocRawData,
+			Objects: []testfile.Object{
+				{Path: "/'g'/'a'", RawDataIndex: testfile.RawDataIndex(DataTypeDoubleFloat, 1, 2), Properties: testfile.WaveformProperties(testfile.Timestamp(startTime.AddSeconds(10)), 1)},
+			},
+			RawData: testfile.RawData([]float64{10, 11}),
+		},
+		testfile.Segment{
+			ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData,
+			Objects: []testfile.Object{
+				{Path: "/'g'/'b'", RawDataIndex: testfile.RawDataIndex(DataTypeDoubleFloat, 1, 2), Properties: testfile.WaveformProperties(testfile.Timestamp(startTime.AddSeconds(4)), 2)},
+			},
+			RawData: testfile.RawData([]float64{40, 42}),
+		},
+	)
+	paths := []string{"/'g'/'a'", "/'g'/'b'"}
+	nan := math.NaN()
+	{
+		target, err := file.ResampleTimeAxis(paths, 0)
+		if assert.NoError(t, err) {
+			assert.Equal(t, startTime, target.StartTime)
+			assert.Equal(t, 1.0, target.Increment)
+			assert.Equal(t, uint64(12), target.Length)
+		}
+	}
+	{
+		resampledData, err := file.Resample(paths, 0, ResampleZeroOrderHold)
+		if assert.NoError(t, err) && assert.Len(t, resampledData.Channels, 2) {
+			assert.Equal(t, []float64{0, 1, 2, 2, 2, 2, 2, 2, 2, 2, 10, 11}, resampledData.Channels[0].Samples)
+			assertSamplesEqual(t, []float64{nan, nan, nan, nan, 40, 40, 42, 42, 42, 42, 42, 42}, resampledData.Channels[1].Samples)
+		}
+	}
+	{
+		var offsets []uint64
+		samples := make(map[string][]float64)
+		target, err := file.ResampleTimeAxis(paths, 0)
+		if !assert.NoError(t, err) {
+			return
+		}
+		err = file.ReadResampled(func(chunk Chunk) error {
+			if assert.Len(t, chunk.Channels, 1) {
+				channel := chunk.Channels[0]
+				if channel.Path == paths[0] {
+					offsets = append(offsets, channel.SampleOffset)
+				}
+				assert.Equal(t, target.Increment, channel.WaveformAttributes.Increment)
+				samples[channel.Path] = append(samples[channel.Path], channel.Samples...)
+			}
+			return nil
+		}, paths, target, ResampleLinear)
+		if assert.NoError(t, err) {
+			// the samples of a are resampled as they are read, before the rest of the file
+			assert.Equal(t, []uint64{0, 2, 11}, offsets)
+			assert.Equal(t, []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, samples[paths[0]])
+			assertSamplesEqual(t, []float64{nan, nan, nan, nan, 40, 41, 42, nan, nan, nan, nan, nan}, samples[paths[1]])
+		}
+	}
+	{
+		// decimation and a sample range, reading the samples at indexes 2 (t = 2s) and 4 (t = 11s)
+		resampledData, err := file.Resample(paths[:1], 0, ResampleLinear, WithSampleRange(1, 5), WithDecimation(2))
+		if assert.NoError(t, err) && assert.Len(t, resampledData.Channels, 1) {
+			assert.Equal(t, startTime.AddSeconds(2), resampledData.TimeAxis.StartTime)
+			assert.Equal(t, 2.0, resampledData.TimeAxis.Increment)
+			assert.Equal(t, []float64{2, 4, 6, 8, 10}, resampledData.Channels[0].Samples)
+		}
+	}
+}
+
+// assertSamplesEqual asserts that the samples are equal, treating NaNs as equal.
+func assertSamplesEqual(t *testing.T, expected []float64, actual []float64) {
+	if !assert.Len(t, actual, len(expected)) {
+		return
+	}
+	for i := range expected {
+		if math.IsNaN(expected[i]) {
+			assert.True(t, math.IsNaN(actual[i]), "sample %d", i)
+		} else {
+			assert.Equal(t, expected[i], actual[i], "sample %d", i)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package tdms
+
+// WithSampleRange reads only the samples whose indexes i within each channel satisfy start <= i < end. Use math.MaxUint64 as end for an unbounded range.
+// If a time range is also specified, only the samples within both ranges are read.
+func WithSampleRange(start uint64, end uint64) ReadOption {
+	return func(options *readOptions) {
+		options.hasSampleRange = true
+		options.sampleStart = start
+		options.sampleEnd = end
+	}
+}
+
+// WithDecimation reads only every factor-th sample of each channel, i.e. the samples whose indexes within the channel are multiples of the factor.
+// The waveform increment of the decimated chunks is multiplied by the factor, and their sample offsets are indexes within the decimated channel.
+func WithDecimation(factor uint64) ReadOption {
+	return func(options *readOptions) {
+		options.decimationFactor = factor
+	}
+}
+
+func (options *readOptions) hasSampleSelection() bool {
+	return options.hasSampleRange || (options.decimationFactor > 1)
+}
+
+// selectedSampleCount returns the number of samples with indexes in [start, end) that are read, after the sample range and decimation are applied.
+func (options *readOptions) selectedSampleCount(start uint64, end uint64) uint64 {
+	if options.hasSampleRange {
+		start = max(start, options.sampleStart)
+		end = min(end, options.sampleEnd)
+	}
+	if start >= end {
+		return 0
+	}
+	factor := max(1, options.decimationFactor)
+	return ceilDiv(end, factor) - ceilDiv(start, factor)
+}
+
+// selectedSampleSpan returns the indexes of the first and last samples of the segment timing that are read with the read options.
+func (options *readOptions) selectedSampleSpan(timing segmentTiming) (uint64, uint64, bool) {
+	start := timing.sampleIndex
+	end := timing.sampleIndex + timing.sampleCount
+	if options.hasTimeRange() {
+		start, end = options.sampleRange(timing, timing.channelStartTime)
+	}
+	if options.hasSampleRange {
+		start = max(start, options.sampleStart)
+		end = min(end, options.sampleEnd)
+	}
+	if start >= end {
+		return 0, 0, false
+	}
+	factor := max(1, options.decimationFactor)
+	first := ceilDiv(start, factor) * factor
+	last := ((end - 1) / factor) * factor
+	if first > last {
+		return 0, 0, false
+	}
+	return first, last, true
+}
+
+// selectsAnySample reports whether any samples of a segment, with the specified timings of its channels, are read with the read options.
+func (options *readOptions) selectsAnySample(timings map[string]segmentTiming) bool {
+	for _, timing := range timings {
+		_, _, ok := options.selectedSampleSpan(timing)
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func ceilDiv(a uint64, b uint64) uint64 {
+	if a == 0 {
+		return 0
+	}
+	return (a-1)/b + 1
+}
+
+// selectSamples trims the channels of a chunk to the sample range, and decimates them. Channels without selected samples are removed.
+func (options *readOptions) selectSamples(chunk Chunk) Chunk {
+	var channels []ChannelData
+	for _, channel := range chunk.Channels {
+		start := channel.SampleOffset
+		end := channel.SampleOffset + uint64(channel.SampleCount())
+		if options.hasSampleRange {
+			start = max(start, options.sampleStart)
+			end = min(end, options.sampleEnd)
+		}
+		if start >= end {
+			continue
+		}
+		n := uint64(channel.SampleSize())
+		factor := max(1, options.decimationFactor)
+		first := ceilDiv(start, factor) * factor
+		if first >= end {
+			continue
+		}
+		if factor == 1 {
+			channel.Samples = channel.Samples[(start-channel.SampleOffset)*n : (end-channel.SampleOffset)*n]
+		} else {
+			var samples []float64
+			for i := first; i < end; i += factor {
+				offset := (i - channel.SampleOffset) * n
+				samples = append(samples, channel.Samples[offset:offset+n]...)
+			}
+			channel.Samples = samples
+			if channel.WaveformAttributes != nil {
+				waveformAttributes := *channel.WaveformAttributes
+				waveformAttributes.Increment *= float64(factor)
+				channel.WaveformAttributes = &waveformAttributes
+			}
+		}
+		channel.SampleOffset = first / factor
+		channels = append(channels, channel)
+	}
+	chunk.Channels = channels
+	return chunk
+}
@@ -0,0 +1,50 @@
+package tdms
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleSelection(t *testing.T) {
+	{
+		options := newReadOptions(WithSampleRange(3, math.MaxUint64), WithDecimation(4))
+		assert.Equal(t, uint64(2), options.selectedSampleCount(0, 10))
+		assert.Equal(t, uint64(0), options.selectedSampleCount(0, 3))
+		assert.Equal(t, uint64(1), options.selectedSampleCount(8, 10))
+
+		options = newReadOptions(WithSampleRange(2, 5))
+		assert.Equal(t, uint64(3), options.selectedSampleCount(0, 10))
+		assert.Equal(t, uint64(1), options.selectedSampleCount(4, 10))
+	}
+	{
+		// chunk holding samples 5 to 9 of a channel of 2-element samples at 10 Hz
+		chunk := Chunk{
+			Channels: []ChannelData{
+				{
+					Path: "/'g'/'c'",
+					WaveformAttributes: &WaveformAttributes{
+						Increment: 0.1,
+					},
+					SampleOffset: 5,
+					Shape:        []int{2},
+					Samples:      []float64{50, 51, 60, 61, 70, 71, 80, 81, 90, 91},
+				},
+			},
+		}
+		options := newReadOptions(WithSampleRange(0, 9), WithDecimation(3))
+		selected := options.selectSamples(chunk)
+		if assert.Len(t, selected.Channels, 1) {
+			channel := selected.Channels[0]
+			assert.Equal(t, []float64{60, 61}, channel.Samples)
+			assert.Equal(t, uint64(2), channel.SampleOffset)
+			assert.InDelta(t, 0.3, channel.WaveformAttributes.Increment, 1e-12)
+			assert.InDelta(t, 0.6, channel.TimeAxis().RelativeTime(0), 1e-12)
+		}
+		assert.Equal(t, 0.1, chunk.Channels[0].WaveformAttributes.Increment)
+
+		options = newReadOptions(WithSampleRange(10, 20))
+		assert.Empty(t, options.selectSamples(chunk).Channels)
+	}
+}
@@ -3,6 +3,7 @@ package tdms
 type Scaler interface {
 	ScaleId() uint32
 	Scale(v any) (float64, error)
+	Info() ScalerInfo
 }
 
 type DAQmxInputScaler interface {
@@ -10,3 +11,12 @@ type DAQmxInputScaler interface {
 
 	ReadFromBuffer(vr *ValueReader, buffers [][]byte) (any, error)
 }
+
+// ScalerInfo describes a scaler in a channel's scaler chain.
+type ScalerInfo struct {
+	Type         string         `json:"type" yaml:"type"`
+	ScaleId      uint32         `json:"scaleId" yaml:"scaleId"`
+	InputSource  *uint          `json:"inputSource,omitempty" yaml:"inputSource,omitempty"`
+	Coefficients []float64      `json:"coefficients,omitempty" yaml:"coefficients,omitempty"`
+	Parameters   map[string]any `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
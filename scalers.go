@@ -49,23 +49,39 @@ func GetScalers(props map[string]any) ([]Scaler, error) {
 				scalerProps[name[len(prefix):]] = value
 			}
 		}
-		scaleType, hasScaleType, err := utils.GetString(scalerProps, "Scale_Type")
+		_, hasScaleType, err := utils.GetString(scalerProps, "Scale_Type")
 		if err != nil {
 			return nil, err
 		}
 		if hasScaleType {
-			switch scaleType {
-			case "Linear":
-				newScaler, err := NewLinearScaler(uint32(i), scalerProps)
-				if err != nil {
-					return nil, err
-				}
-				scalers = append(scalers, newScaler)
-			default:
-				return nil, fmt.Errorf("unknown scale type '%v'", scaleType)
+			newScaler, err := NewScaler(uint32(i), scalerProps)
+			if err != nil {
+				return nil, err
 			}
+			scalers = append(scalers, newScaler)
 		}
 	}
 
 	return scalers, nil
 }
+
+// NewScaler creates a scaler from scaler properties, i.e. NI_Scale[n]_* properties with the prefix removed.
+func NewScaler(scaleId uint32, props map[string]any) (Scaler, error) {
+	scaleType, hasScaleType, err := utils.GetString(props, "Scale_Type")
+	if err != nil {
+		return nil, err
+	}
+	if !hasScaleType {
+		return nil, fmt.Errorf("Scale_Type not specified")
+	}
+	switch scaleType {
+	case "Linear":
+		newScaler, err := NewLinearScaler(scaleId, props)
+		if err != nil {
+			return nil, err
+		}
+		return newScaler, nil
+	default:
+		return nil, fmt.Errorf("unknown scale type '%v'", scaleType)
+	}
+}
@@ -28,6 +28,9 @@ func ReadSegment(r io.Reader, previousSegment *Segment) (*Segment, error) {
 				In("Segment").
 				Wrapf(err, "invalid metadata")
 		}
+	} else if previousSegment != nil {
+		// segments without metadata use the object list of the previous segment
+		segment.MetaData = previousSegment.MetaData
 	}
 
 	return &segment, nil
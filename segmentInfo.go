@@ -0,0 +1,120 @@
+package tdms
+
+import (
+	"io"
+)
+
+// SegmentInfo describes a segment, for inspection and debugging.
+type SegmentInfo struct {
+	Index             int         `json:"index" yaml:"index"`
+	Offset            int64       `json:"offset" yaml:"offset"`
+	Type              SegmentType `json:"type" yaml:"type"`
+	VersionNumber     uint32      `json:"versionNumber" yaml:"versionNumber"`
+	ToC               uint32      `json:"toc" yaml:"toc"`
+	Flags             []string    `json:"flags" yaml:"flags"`
+	NextSegmentOffset uint64      `json:"nextSegmentOffset" yaml:"nextSegmentOffset"`
+	RawDataOffset     uint64      `json:"rawDataOffset" yaml:"rawDataOffset"`
+	RawDataSize       uint64      `json:"rawDataSize" yaml:"rawDataSize"`
+	// MetaDataInherited is true if the segment has no metadata, and uses the object list of the previous segment.
+	MetaDataInherited bool         `json:"metaDataInherited" yaml:"metaDataInherited"`
+	Objects           []ObjectInfo `json:"objects" yaml:"objects"`
+}
+
+// ObjectInfo describes an object of a segment's object list.
+type ObjectInfo struct {
+	Path          string            `json:"path" yaml:"path"`
+	PropertyNames []string          `json:"propertyNames,omitempty" yaml:"propertyNames,omitempty"`
+	RawDataIndex  *RawDataIndexInfo `json:"rawDataIndex,omitempty" yaml:"rawDataIndex,omitempty"`
+}
+
+// RawDataIndexInfo describes a raw data index.
+type RawDataIndexInfo struct {
+	Type             string       `json:"type" yaml:"type"`
+	DataType         string       `json:"dataType" yaml:"dataType"`
+	ArrayDimension   uint32       `json:"arrayDimension" yaml:"arrayDimension"`
+	ChunkSize        uint64       `json:"chunkSize" yaml:"chunkSize"`
+	TotalSizeInBytes uint64       `json:"totalSizeInBytes" yaml:"totalSizeInBytes"`
+	RawDataWidths    []uint32     `json:"rawDataWidths,omitempty" yaml:"rawDataWidths,omitempty"`
+	Scalers          []ScalerInfo `json:"scalers,omitempty" yaml:"scalers,omitempty"`
+}
+
+// RawDataSize returns the size in bytes of the raw data of the segment.
+func (segment *Segment) RawDataSize() uint64 {
+	return segment.LeadIn.NextSegmentOffset - segment.LeadIn.RawDataOffset
+}
+
+// Info returns a description of the segment.
+func (segment *Segment) Info(index int) SegmentInfo {
+	info := SegmentInfo{
+		Index:             index,
+		Offset:            segment.Offset,
+		Type:              segment.Type,
+		VersionNumber:     segment.LeadIn.VersionNumber,
+		ToC:               uint32(segment.LeadIn.ToC),
+		Flags:             segment.LeadIn.ToC.Flags(),
+		NextSegmentOffset: segment.LeadIn.NextSegmentOffset,
+		RawDataOffset:     segment.LeadIn.RawDataOffset,
+		RawDataSize:       segment.RawDataSize(),
+		MetaDataInherited: !segment.LeadIn.ToC.MetaData() && (segment.MetaData != nil),
+	}
+	if segment.MetaData == nil {
+		return info
+	}
+	for _, object := range segment.MetaData.Objects() {
+		objectInfo := ObjectInfo{
+			Path: object.Path,
+		}
+		if segment.LeadIn.ToC.MetaData() {
+			objectInfo.PropertyNames = object.PropertyNames
+		}
+		if object.RawDataIndex != nil {
+			objectInfo.RawDataIndex = newRawDataIndexInfo(object.RawDataIndex)
+		}
+		info.Objects = append(info.Objects, objectInfo)
+	}
+	return info
+}
+
+func newRawDataIndexInfo(rawDataIndex RawDataIndex) *RawDataIndexInfo {
+	info := &RawDataIndexInfo{
+		DataType:         rawDataIndex.GetDataType().String(),
+		ArrayDimension:   rawDataIndex.GetArrayDimension(),
+		ChunkSize:        rawDataIndex.GetChunkSize(),
+		TotalSizeInBytes: rawDataIndex.GetTotalSizeInBytes(),
+	}
+	switch index := rawDataIndex.(type) {
+	case *DAQmxRawDataIndex:
+		info.Type = "DAQmx"
+		info.RawDataWidths = index.RawDataWidths
+		for _, scaler := range index.Scalers {
+			if scaler != nil {
+				info.Scalers = append(info.Scalers, scaler.Info())
+			}
+		}
+	case *DefaultRawDataIndex:
+		info.Type = "Default"
+	}
+	return info
+}
+
+// IterateSegments calls the handler for each segment of the file, in file order.
+func (file *File) IterateSegments(handler func(segment *Segment) error) error {
+	err := file.iterateSegments(handler)
+	if (err != nil) && (err != io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// SegmentInfos returns a description of each segment of the file.
+func (file *File) SegmentInfos() ([]SegmentInfo, error) {
+	var infos []SegmentInfo
+	err := file.IterateSegments(func(segment *Segment) error {
+		infos = append(infos, segment.Info(len(infos)))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
@@ -0,0 +1,99 @@
+package tdms
+
+import (
+	"testing"
+
+	"github.com/ngyewch/tdms-go/internal/testfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegmentInfo(t *testing.T) {
+	file := openTestFile(t,
+		testfile.Segment{
+			ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData,
+			Objects: []testfile.Object{
+				{Path: "/", Properties: []testfile.Property{{Name: "name", Value: "test"}}},
+				{Path: "/'g'"},
+				{Path: "/'g'/'a'", RawDataIndex: testfile.RawDataIndex(DataTypeI16, 1, 2), Properties: []testfile.Property{{Name: "unit_string", Value: "V"}, {Name: "gain", Value: 2.0}}},
+			},
+			RawData: testfile.RawData([]int16{1, 2}),
+		},
+		testfile.Segment{
+			ToC:     testfile.TocRawData,
+			RawData: testfile.RawData([]int16{3, 4}),
+		},
+		testfile.Segment{
+			ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData | testfile.TocDAQmxRawData,
+			Objects: []testfile.Object{
+				{Path: "/'g'/'b'", RawDataIndex: testDAQmxRawDataIndex(1, 1, []testDAQmxScaler{{daqmxDataType: 3, rawBufferIndex: 0, byteOffset: 2, scaleId: 0}}, []uint32{4})},
+			},
+			RawData: testfile.RawData(int16(0), int16(5)),
+		},
+	)
+	infos, err := file.SegmentInfos()
+	if !assert.NoError(t, err) || !assert.Len(t, infos, 3) {
+		return
+	}
+	for _, test := range []struct {
+		index             int
+		flags             []string
+		rawDataSize       uint64
+		metaDataInherited bool
+		objectPaths       []string
+		propertyNames     [][]string
+	}{
+		{0, []string{"MetaData", "NewObjList", "RawData"}, 4, false, []string{"/", "/'g'", "/'g'/'a'"}, [][]string{{"name"}, nil, {"unit_string", "gain"}}},
+		{1, []string{"RawData"}, 4, true, []string{"/", "/'g'", "/'g'/'a'"}, [][]string{nil, nil, nil}},
+		{2, []string{"MetaData", "NewObjList", "RawData", "DAQmxRawData"}, 4, false, []string{"/'g'/'b'"}, [][]string{nil}},
+	} {
+		info := infos[test.index]
+		assert.Equal(t, test.index, info.Index)
+		assert.Equal(t, SegmentTypeTDSm, info.Type)
+		assert.Equal(t, uint32(4713), info.VersionNumber)
+		assert.Equal(t, test.flags, info.Flags, "segment %d", test.index)
+		assert.Equal(t, test.rawDataSize, info.RawDataSize, "segment %d", test.index)
+		assert.Equal(t, info.NextSegmentOffset-info.RawDataOffset, info.RawDataSize, "segment %d", test.index)
+		assert.Equal(t, test.metaDataInherited, info.MetaDataInherited, "segment %d", test.index)
+		var objectPaths []string
+		var propertyNames [][]string
+		for _, object := range info.Objects {
+			objectPaths = append(objectPaths, object.Path)
+			propertyNames = append(propertyNames, object.PropertyNames)
+		}
+		assert.Equal(t, test.objectPaths, objectPaths, "segment %d", test.index)
+		assert.Equal(t, test.propertyNames, propertyNames, "segment %d", test.index)
+		if test.index > 0 {
+			previous := infos[test.index-1]
+			assert.Equal(t, previous.Offset+int64(previous.NextSegmentOffset)+leadInByteLength, info.Offset, "segment %d", test.index)
+		}
+	}
+	assert.Equal(t, int64(0), infos[0].Offset)
+	assert.Equal(t, uint64(0), infos[1].RawDataOffset)
+	assert.Nil(t, infos[0].Objects[0].RawDataIndex)
+	assert.Equal(t, &RawDataIndexInfo{
+		Type:             "Default",
+		DataType:         "I16",
+		ArrayDimension:   1,
+		ChunkSize:        2,
+		TotalSizeInBytes: 4,
+	}, infos[0].Objects[2].RawDataIndex)
+	assert.Equal(t, &RawDataIndexInfo{
+		Type:             "DAQmx",
+		DataType:         "DAQmxRawData",
+		ArrayDimension:   1,
+		ChunkSize:        1,
+		TotalSizeInBytes: 4,
+		RawDataWidths:    []uint32{4},
+		Scalers: []ScalerInfo{
+			{
+				Type: "DAQmxFormatChanging",
+				Parameters: map[string]any{
+					"DataType":                     "I16",
+					"RawBufferIndex":               uint32(0),
+					"RawByteOffsetWithinTheStride": uint32(2),
+					"SampleFormatBitmap":           uint32(0),
+				},
+			},
+		},
+	}, infos[2].Objects[0].RawDataIndex)
+}
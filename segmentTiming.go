@@ -0,0 +1,174 @@
+package tdms
+
+import (
+	"io"
+)
+
+// segmentTiming describes the timing of the samples of a channel within a segment.
+type segmentTiming struct {
+	path          string
+	segmentIndex  int
+	segmentOffset int64
+	// sampleIndex is the index of the first sample of the segment within the channel.
+	sampleIndex uint64
+	sampleCount uint64
+	// startTime is the time of the first sample of the segment. Segments that do not restate wf_start_time continue from the previous segment.
+	startTime          Timestamp
+	waveformAttributes *WaveformAttributes
+	// channelStartTime is the time of the first sample of the channel.
+	channelStartTime Timestamp
+	// axisAttributes maps the sample indexes of the channel to the times of the samples of the segment. Its start time is the channel's
+	// first wf_start_time, and its start offset is chosen so that the sample at sampleIndex is at startTime. It is shared by the
+	// segments continuing from the previous segment.
+	axisAttributes *WaveformAttributes
+	// restarted is true if wf_start_time was restated after the channel's previous segment with samples.
+	restarted         bool
+	expectedStartTime Timestamp
+}
+
+func (timing segmentTiming) sampleTime(sampleIndex uint64) Timestamp {
+	return timing.startTime.AddSeconds(float64(sampleIndex-timing.sampleIndex) * timing.waveformAttributes.Increment)
+}
+
+// segmentTimer computes the timing of the channels of successive segments.
+type segmentTimer struct {
+	file         *File
+	segmentIndex int
+	channels     map[string]*timedChannel
+	lastTimings  map[string]segmentTiming
+}
+
+// timedChannel holds the properties of a channel as of the current segment, replayed from the channel's property history.
+type timedChannel struct {
+	node       *Node
+	properties map[string]any
+	// historyIndex is the index of the first change of the property history that has not been replayed.
+	historyIndex int
+}
+
+func (file *File) newSegmentTimer() *segmentTimer {
+	return &segmentTimer{
+		file:         file,
+		segmentIndex: -1,
+		channels:     make(map[string]*timedChannel),
+		lastTimings:  make(map[string]segmentTiming),
+	}
+}
+
+func newTimedChannel(node *Node) *timedChannel {
+	return &timedChannel{
+		node:       node,
+		properties: make(map[string]any),
+	}
+}
+
+// replay applies the changes of the channel's property history up to and including the segment at the specified offset,
+// and reports whether wf_start_time was set.
+func (channel *timedChannel) replay(segmentOffset int64) bool {
+	restated := false
+	history := channel.node.PropertyChanges()
+	for (channel.historyIndex < len(history)) && (history[channel.historyIndex].SegmentOffset <= segmentOffset) {
+		change := history[channel.historyIndex]
+		channel.properties[change.Name] = change.Value
+		if change.Name == "wf_start_time" {
+			restated = true
+		}
+		channel.historyIndex++
+	}
+	return restated
+}
+
+// next returns the timing of each channel with samples in the segment, which is the segment following the previous call.
+// A segment restarts the timing of a channel if wf_start_time was set by the segment, or by a segment since the channel's previous
+// segment with samples.
+func (timer *segmentTimer) next(segment *Segment) (map[string]segmentTiming, error) {
+	timer.segmentIndex++
+	if (segment.MetaData == nil) || !segment.LeadIn.ToC.RawData() {
+		return nil, nil
+	}
+	sampleCounts, err := timer.file.getSegmentSampleCounts(segment)
+	if err != nil {
+		return nil, err
+	}
+	timings := make(map[string]segmentTiming)
+	for _, object := range segment.MetaData.Objects() {
+		sampleCount := sampleCounts[object.Path]
+		if sampleCount == 0 {
+			continue
+		}
+		channel, exists := timer.channels[object.Path]
+		if !exists {
+			node := timer.file.Node(object.Path)
+			if node == nil {
+				continue
+			}
+			channel = newTimedChannel(node)
+			timer.channels[object.Path] = channel
+		}
+		restated := channel.replay(segment.Offset)
+		waveformAttributes, err := GetWaveformAttributes(channel.properties)
+		if err != nil {
+			return nil, err
+		}
+		timing := segmentTiming{
+			path:               object.Path,
+			segmentIndex:       timer.segmentIndex,
+			segmentOffset:      segment.Offset,
+			sampleCount:        sampleCount,
+			startTime:          waveformAttributes.AbsoluteTime(0),
+			waveformAttributes: waveformAttributes,
+		}
+		previous, exists := timer.lastTimings[object.Path]
+		if !exists {
+			timing.channelStartTime = timing.startTime
+			timing.axisAttributes = waveformAttributes
+		} else {
+			timing.sampleIndex = previous.sampleIndex + previous.sampleCount
+			timing.expectedStartTime = previous.sampleTime(timing.sampleIndex)
+			timing.channelStartTime = previous.channelStartTime
+			if restated {
+				timing.restarted = true
+			} else {
+				timing.startTime = timing.expectedStartTime
+			}
+			timing.axisAttributes = previous.axisAttributes
+			if timing.restarted || (waveformAttributes.Increment != previous.axisAttributes.Increment) {
+				axisAttributes := *waveformAttributes
+				axisAttributes.StartTime = previous.axisAttributes.StartTime
+				axisAttributes.StartOffset = timing.startTime.SubSeconds(axisAttributes.StartTime) - float64(timing.sampleIndex)*axisAttributes.Increment
+				timing.axisAttributes = &axisAttributes
+			}
+		}
+		timer.lastTimings[object.Path] = timing
+		timings[object.Path] = timing
+	}
+	return timings, nil
+}
+
+// getSegmentTimings walks the segments of the file and returns the timing of each channel in each segment containing samples of the channel.
+func (file *File) getSegmentTimings() (map[string][]segmentTiming, error) {
+	timingMap := make(map[string][]segmentTiming)
+	timer := file.newSegmentTimer()
+	err := file.iterateSegments(func(segment *Segment) error {
+		timings, err := timer.next(segment)
+		if err != nil {
+			return err
+		}
+		if len(timings) == 0 {
+			return nil
+		}
+		for _, object := range segment.MetaData.Objects() {
+			timing, exists := timings[object.Path]
+			if exists {
+				timingMap[object.Path] = append(timingMap[object.Path], timing)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if err != io.EOF {
+			return nil, err
+		}
+	}
+	return timingMap, nil
+}
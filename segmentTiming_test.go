@@ -0,0 +1,79 @@
+package tdms
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ngyewch/tdms-go/internal/testfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropertyHistory(t *testing.T) {
+	startTime := NewTimestamp(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	path := "/'g'/'a'"
+	file := openTestFile(t,
+		testfile.Segment{
+			ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData,
+			Objects: []testfile.Object{
+				{Path: "/"},
+				{Path: "/'g'"},
+				{Path: path, RawDataIndex: testfile.RawDataIndex(DataTypeDoubleFloat, 1, 2), Properties: append(testfile.WaveformProperties(testfile.Timestamp(startTime), 1), testfile.Property{Name: "gain", Value: int32(1)})},
+			},
+			RawData: testfile.RawData([]float64{0, 1}),
+		},
+		testfile.Segment{
+			ToC:     testfile.TocRawData,
+			RawData: testfile.RawData([]float64{2, 3}),
+		},
+		// restates the start time and the gain, without raw data
+		testfile.Segment{
+			ToC: testfile.TocMetaData,
+			Objects: []testfile.Object{
+				{Path: path, RawDataIndex: testfile.RawDataIndexSameAsPreviousSegment, Properties: []testfile.Property{{Name: "wf_start_time", Value: testfile.Timestamp(startTime.AddSeconds(10))}, {Name: "gain", Value: int32(2)}}},
+			},
+		},
+		testfile.Segment{
+			ToC: testfile.TocMetaData | testfile.TocRawData,
+			Objects: []testfile.Object{
+				{Path: path, RawDataIndex: testfile.RawDataIndexSameAsPreviousSegment},
+			},
+			RawData: testfile.RawData([]float64{10, 11}),
+		},
+	)
+	var segmentOffsets []int64
+	err := file.iterateSegments(func(segment *Segment) error {
+		segmentOffsets = append(segmentOffsets, segment.Offset)
+		return nil
+	})
+	if (err != nil) && (err != io.EOF) {
+		t.Fatal(err)
+	}
+	channel := file.Node(path)
+	if !assert.NotNil(t, channel) {
+		return
+	}
+	assert.Equal(t, []PropertyChange{
+		{Name: "gain", Value: int32(1), DataType: DataTypeI32, SegmentIndex: 0, SegmentOffset: 0},
+		{Name: "gain", Value: int32(2), DataType: DataTypeI32, SegmentIndex: 2, SegmentOffset: segmentOffsets[2]},
+	}, channel.PropertyHistory("gain"))
+	assert.Equal(t, []PropertyChange{
+		{Name: "wf_start_time", Value: startTime, DataType: DataTypeTimestamp, SegmentIndex: 0, SegmentOffset: 0},
+		{Name: "wf_start_time", Value: startTime.AddSeconds(10), DataType: DataTypeTimestamp, SegmentIndex: 2, SegmentOffset: segmentOffsets[2]},
+	}, channel.PropertyHistory("wf_start_time"))
+	assert.Len(t, channel.PropertyChanges(), 6)
+	assert.Equal(t, int32(2), channel.Properties().Collect()["gain"])
+
+	// the timing follows the history: the start time restated by the segment without raw data restarts the next segment with samples
+	timingMap, err := file.getSegmentTimings()
+	if assert.NoError(t, err) && assert.Len(t, timingMap[path], 3) {
+		timings := timingMap[path]
+		assert.False(t, timings[1].restarted)
+		assert.Equal(t, startTime.AddSeconds(2), timings[1].startTime)
+		assert.True(t, timings[2].restarted)
+		assert.Equal(t, 3, timings[2].segmentIndex)
+		assert.Equal(t, uint64(4), timings[2].sampleIndex)
+		assert.Equal(t, startTime.AddSeconds(4), timings[2].expectedStartTime)
+		assert.Equal(t, startTime.AddSeconds(10), timings[2].startTime)
+	}
+}
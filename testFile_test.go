@@ -0,0 +1,62 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/ngyewch/tdms-go/internal/testfile"
+)
+
+// testDAQmxScaler describes a DAQmx format changing scaler, with the DAQmx data type code of the raw values.
+type testDAQmxScaler struct {
+	daqmxDataType  uint32
+	rawBufferIndex uint32
+	byteOffset     uint32
+	scaleId        uint32
+}
+
+// testDAQmxRawDataIndex returns a DAQmx format changing scaler raw data index.
+func testDAQmxRawDataIndex(chunkSize uint64, arrayDimension uint32, scalers []testDAQmxScaler, rawDataWidths []uint32) []byte {
+	b := binary.LittleEndian.AppendUint32(nil, RawDataIndexTypeDAQmxFormatChangingScalerType)
+	b = binary.LittleEndian.AppendUint32(b, uint32(DataTypeDAQmxRawData))
+	b = binary.LittleEndian.AppendUint32(b, arrayDimension)
+	b = binary.LittleEndian.AppendUint64(b, chunkSize)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(scalers)))
+	for _, scaler := range scalers {
+		for _, v := range []uint32{scaler.daqmxDataType, scaler.rawBufferIndex, scaler.byteOffset, 0, scaler.scaleId} {
+			b = binary.LittleEndian.AppendUint32(b, v)
+		}
+	}
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(rawDataWidths)))
+	for _, rawDataWidth := range rawDataWidths {
+		b = binary.LittleEndian.AppendUint32(b, rawDataWidth)
+	}
+	return b
+}
+
+// openTestFile writes the segments to a TDMS file, and opens it. The file is closed when the test ends.
+func openTestFile(t *testing.T, segments ...testfile.Segment) *File {
+	file, err := OpenFile(testfile.Write(t, segments...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = file.Close()
+	})
+	return file
+}
+
+// readTestSamples reads the samples of each channel of the file.
+func readTestSamples(t *testing.T, file *File, options ...ReadOption) map[string][]float64 {
+	samples := make(map[string][]float64)
+	err := file.ReadData(func(chunk Chunk) error {
+		for _, channel := range chunk.Channels {
+			samples[channel.Path] = append(samples[channel.Path], channel.Samples...)
+		}
+		return nil
+	}, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return samples
+}
@@ -0,0 +1,128 @@
+package tdms
+
+import (
+	"fmt"
+	"iter"
+	"sort"
+)
+
+// TimeAxis maps the sample indexes of a channel to time, using the channel's waveform attributes.
+type TimeAxis struct {
+	StartTime   Timestamp
+	StartOffset float64
+	Increment   float64
+	// FirstSample is the index of the first sample of the axis within the channel.
+	FirstSample uint64
+	Length      uint64
+	// pieces holds the mapping of the channel's samples from the segments which restart wf_start_time or change wf_increment on.
+	pieces []timeAxisPiece
+}
+
+// timeAxisPiece maps the sample indexes of a channel, from firstSample on, to time relative to the start time of the axis.
+type timeAxisPiece struct {
+	firstSample uint64
+	startOffset float64
+	increment   float64
+}
+
+func NewTimeAxis(waveformAttributes *WaveformAttributes, firstSample uint64, length uint64) *TimeAxis {
+	return &TimeAxis{
+		StartTime:   waveformAttributes.StartTime,
+		StartOffset: waveformAttributes.StartOffset,
+		Increment:   waveformAttributes.Increment,
+		FirstSample: firstSample,
+		Length:      length,
+	}
+}
+
+// RelativeTime returns the time of the i-th sample of the axis, in seconds relative to the start time.
+func (axis *TimeAxis) RelativeTime(i uint64) float64 {
+	sampleIndex := axis.FirstSample + i
+	n := sort.Search(len(axis.pieces), func(n int) bool {
+		return axis.pieces[n].firstSample > sampleIndex
+	})
+	if n > 0 {
+		piece := axis.pieces[n-1]
+		return piece.startOffset + float64(sampleIndex)*piece.increment
+	}
+	return axis.StartOffset + float64(sampleIndex)*axis.Increment
+}
+
+// AbsoluteTime returns the time of the i-th sample of the axis.
+func (axis *TimeAxis) AbsoluteTime(i uint64) Timestamp {
+	return axis.StartTime.AddSeconds(axis.RelativeTime(i))
+}
+
+// RelativeTimes returns a lazy sequence of the relative times of all samples of the axis.
+func (axis *TimeAxis) RelativeTimes() iter.Seq2[uint64, float64] {
+	return func(yield func(uint64, float64) bool) {
+		for i := uint64(0); i < axis.Length; i++ {
+			if !yield(i, axis.RelativeTime(i)) {
+				return
+			}
+		}
+	}
+}
+
+// AbsoluteTimes returns a lazy sequence of the absolute times of all samples of the axis.
+func (axis *TimeAxis) AbsoluteTimes() iter.Seq2[uint64, Timestamp] {
+	return func(yield func(uint64, Timestamp) bool) {
+		for i := uint64(0); i < axis.Length; i++ {
+			if !yield(i, axis.AbsoluteTime(i)) {
+				return
+			}
+		}
+	}
+}
+
+// CollectRelativeTimes returns the relative times of all samples of the axis.
+func (axis *TimeAxis) CollectRelativeTimes() []float64 {
+	relativeTimes := make([]float64, axis.Length)
+	for i, relativeTime := range axis.RelativeTimes() {
+		relativeTimes[i] = relativeTime
+	}
+	return relativeTimes
+}
+
+// Units returns the units of the relative times, e.g. "seconds since 2024-01-01T00:00:00Z".
+func (axis *TimeAxis) Units() string {
+	return "seconds since " + axis.StartTime.String()
+}
+
+// TimeAxis returns the time axis of the specified channel across all segments. The start time of the axis is the channel's first wf_start_time,
+// and the times of the samples of segments restating wf_start_time or changing wf_increment follow those segments' waveform attributes.
+func (file *File) TimeAxis(path string) (*TimeAxis, error) {
+	node := file.Node(path)
+	if node == nil {
+		return nil, fmt.Errorf("could not find object node")
+	}
+	timingMap, err := file.getSegmentTimings()
+	if err != nil {
+		return nil, err
+	}
+	timings := timingMap[path]
+	if len(timings) == 0 {
+		waveformAttributes, err := GetWaveformAttributes(node.Properties().Collect())
+		if err != nil {
+			return nil, err
+		}
+		return NewTimeAxis(waveformAttributes, 0, 0), nil
+	}
+	last := timings[len(timings)-1]
+	axis := NewTimeAxis(timings[0].axisAttributes, 0, last.sampleIndex+last.sampleCount)
+	for i, timing := range timings[1:] {
+		if timing.axisAttributes != timings[i].axisAttributes {
+			axis.pieces = append(axis.pieces, timeAxisPiece{
+				firstSample: timing.sampleIndex,
+				startOffset: timing.axisAttributes.StartOffset,
+				increment:   timing.axisAttributes.Increment,
+			})
+		}
+	}
+	return axis, nil
+}
+
+// TimeAxis returns the time axis of the samples in the chunk.
+func (channelData ChannelData) TimeAxis() *TimeAxis {
+	return NewTimeAxis(channelData.WaveformAttributes, channelData.SampleOffset, uint64(channelData.SampleCount()))
+}
@@ -0,0 +1,79 @@
+package tdms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngyewch/tdms-go/internal/testfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeAxis(t *testing.T) {
+	{
+		waveformAttributes := &WaveformAttributes{
+			StartTime:   NewTimestamp(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)),
+			StartOffset: 0.5,
+			Increment:   0.25,
+		}
+		axis := NewTimeAxis(waveformAttributes, 2, 3)
+		assert.Equal(t, []float64{1.0, 1.25, 1.5}, axis.CollectRelativeTimes())
+		assert.Equal(t, time.Date(2024, time.January, 1, 0, 0, 1, 250_000_000, time.UTC), axis.AbsoluteTime(1).Time())
+		assert.Equal(t, "seconds since 2024-01-01T00:00:00Z", axis.Units())
+
+		var visited []uint64
+		for i := range axis.AbsoluteTimes() {
+			visited = append(visited, i)
+			if i == 1 {
+				break
+			}
+		}
+		assert.Equal(t, []uint64{0, 1}, visited)
+	}
+	{
+		// wf_start_time restarts 10 s after the start in the second segment, and the third segment continues from the second
+		startTime := NewTimestamp(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+		file := openTestFile(t,
+			testfile.Segment{
+				ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData,
+				Objects: []testfile.Object{
+					{Path: "/"},
+					{Path: "/'g'"},
+					{Path: "/'g'/'c'", RawDataIndex: testfile.RawDataIndex(DataTypeI32, 1, 3), Properties: testfile.WaveformProperties(testfile.Timestamp(startTime), 1)},
+				},
+				RawData: testfile.RawData([]int32{0, 1, 2}),
+			},
+			testfile.Segment{
+				ToC: testfile.TocMetaData | testfile.TocRawData,
+				Objects: []testfile.Object{
+					{Path: "/'g'/'c'", RawDataIndex: testfile.RawDataIndex(DataTypeI32, 1, 2), Properties: []testfile.Property{{Name: "wf_start_time", Value: testfile.Timestamp(startTime.AddSeconds(10))}}},
+				},
+				RawData: testfile.RawData([]int32{3, 4}),
+			},
+			testfile.Segment{
+				ToC:     testfile.TocRawData,
+				RawData: testfile.RawData([]int32{5, 6}),
+			},
+		)
+		expected := []float64{0, 1, 2, 10, 11, 12, 13}
+
+		axis, err := file.TimeAxis("/'g'/'c'")
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, axis.CollectRelativeTimes())
+			assert.Equal(t, "seconds since 2024-01-01T00:00:00Z", axis.Units())
+			assert.Equal(t, startTime.AddSeconds(12), axis.AbsoluteTime(5))
+		}
+
+		var relativeTimes []float64
+		err = file.ReadData(func(chunk Chunk) error {
+			for _, channel := range chunk.Channels {
+				timeAxis := channel.TimeAxis()
+				assert.Equal(t, "seconds since 2024-01-01T00:00:00Z", timeAxis.Units())
+				relativeTimes = append(relativeTimes, timeAxis.CollectRelativeTimes()...)
+			}
+			return nil
+		})
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, relativeTimes)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package tdms
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// TimeBound is a bound of a time range, either an absolute time or an offset in seconds relative to the first sample of each channel.
+type TimeBound struct {
+	Time     Timestamp
+	Offset   float64
+	Relative bool
+}
+
+func AbsoluteTimeBound(t time.Time) *TimeBound {
+	return &TimeBound{
+		Time: NewTimestamp(t),
+	}
+}
+
+func RelativeTimeBound(seconds float64) *TimeBound {
+	return &TimeBound{
+		Offset:   seconds,
+		Relative: true,
+	}
+}
+
+// ParseTimeBound parses a time bound, either a number of seconds relative to the first sample, or an ISO 8601 timestamp.
+func ParseTimeBound(s string) (*TimeBound, error) {
+	seconds, err := strconv.ParseFloat(s, 64)
+	if err == nil {
+		return RelativeTimeBound(seconds), nil
+	}
+	ts, err := ParseTimestamp(s)
+	if err != nil {
+		return nil, err
+	}
+	return &TimeBound{
+		Time: ts,
+	}, nil
+}
+
+func (bound *TimeBound) String() string {
+	if bound.Relative {
+		return strconv.FormatFloat(bound.Offset, 'g', -1, 64)
+	}
+	return bound.Time.String()
+}
+
+func (bound *TimeBound) resolve(channelStartTime Timestamp) Timestamp {
+	if bound.Relative {
+		return channelStartTime.AddSeconds(bound.Offset)
+	}
+	return bound.Time
+}
+
+// WithTimeRange reads only the samples whose times t satisfy from <= t < to. A nil bound is unbounded.
+// Sample times are derived from the waveform attributes of each segment, honouring restarts of wf_start_time.
+func WithTimeRange(from *TimeBound, to *TimeBound) ReadOption {
+	return func(options *readOptions) {
+		options.from = from
+		options.to = to
+	}
+}
+
+func (options *readOptions) hasTimeRange() bool {
+	return (options.from != nil) || (options.to != nil)
+}
+
+// sampleRange returns the range [start, end) of the channel's sample indexes within the segment timing that lie within the time range.
+func (options *readOptions) sampleRange(timing segmentTiming, channelStartTime Timestamp) (uint64, uint64) {
+	start := timing.sampleIndex
+	end := timing.sampleIndex + timing.sampleCount
+	increment := timing.waveformAttributes.Increment
+	if increment <= 0 {
+		return start, end
+	}
+	// index of the first sample at or after t, relative to the start of the segment
+	firstIndexAt := func(t Timestamp) float64 {
+		return math.Ceil(t.SubSeconds(timing.startTime)/increment - 1e-9)
+	}
+	if options.from != nil {
+		i := firstIndexAt(options.from.resolve(channelStartTime))
+		if i > 0 {
+			start = timing.sampleIndex + min(uint64(i), timing.sampleCount)
+		}
+	}
+	if options.to != nil {
+		i := firstIndexAt(options.to.resolve(channelStartTime))
+		if i <= 0 {
+			end = timing.sampleIndex
+		} else {
+			end = timing.sampleIndex + min(uint64(i), timing.sampleCount)
+		}
+	}
+	return start, max(start, end)
+}
+
+// selectTimeRange trims the channels of a chunk, read from the segment with the specified timings, to the time range.
+// Channels without samples in the time range are removed.
+func (options *readOptions) selectTimeRange(chunk Chunk, timings map[string]segmentTiming) Chunk {
+	var channels []ChannelData
+	for _, channel := range chunk.Channels {
+		timing, exists := timings[channel.Path]
+		if !exists {
+			continue
+		}
+		start, end := options.sampleRange(timing, timing.channelStartTime)
+		chunkStart := channel.SampleOffset
+		chunkEnd := channel.SampleOffset + uint64(channel.SampleCount())
+		start = max(start, chunkStart)
+		end = min(end, chunkEnd)
+		if start >= end {
+			continue
+		}
+		n := uint64(channel.SampleSize())
+		channel.Samples = channel.Samples[(start-chunkStart)*n : (end-chunkStart)*n]
+		channel.SampleOffset = start
+		channels = append(channels, channel)
+	}
+	chunk.Channels = channels
+	return chunk
+}
+
+// getTimeRangeSampleCounts returns the number of samples of each channel within the time range, and the sample range if specified.
+func (file *File) getTimeRangeSampleCounts(options *readOptions) (map[string]uint64, error) {
+	timingMap, err := file.getSegmentTimings()
+	if err != nil {
+		return nil, err
+	}
+	sampleCounts := make(map[string]uint64)
+	for path, timings := range timingMap {
+		for _, timing := range timings {
+			start, end := options.sampleRange(timing, timing.channelStartTime)
+			sampleCounts[path] += options.selectedSampleCount(start, end)
+		}
+	}
+	return sampleCounts, nil
+}
+
+// TimeRangeData holds the samples of a channel within a time range.
+type TimeRangeData struct {
+	Path string
+	Node *Node
+	// FirstSample is the index of the first sample within the channel.
+	FirstSample uint64
+	// Shape is the shape of each sample, or nil for scalar samples.
+	Shape []int
+	// Samples holds the values of all samples, in row-major order.
+	Samples []float64
+	// Times holds the time of each sample, honouring restarts of wf_start_time.
+	Times []Timestamp
+}
+
+// ReadTimeRange reads the samples of the channel whose times t satisfy from <= t < to. A zero time is unbounded.
+func (file *File) ReadTimeRange(path string, from time.Time, to time.Time, options ...ReadOption) (*TimeRangeData, error) {
+	var fromBound, toBound *TimeBound
+	if !from.IsZero() {
+		fromBound = AbsoluteTimeBound(from)
+	}
+	if !to.IsZero() {
+		toBound = AbsoluteTimeBound(to)
+	}
+	return file.readTimeRange(path, fromBound, toBound, options...)
+}
+
+// ReadRelativeTimeRange reads the samples of the channel whose times t, in seconds relative to the first sample of the channel, satisfy from <= t < to.
+func (file *File) ReadRelativeTimeRange(path string, from float64, to float64, options ...ReadOption) (*TimeRangeData, error) {
+	return file.readTimeRange(path, RelativeTimeBound(from), RelativeTimeBound(to), options...)
+}
+
+func (file *File) readTimeRange(path string, from *TimeBound, to *TimeBound, options ...ReadOption) (*TimeRangeData, error) {
+	data := &TimeRangeData{
+		Path: path,
+		Node: file.Node(path),
+	}
+	first := true
+	err := file.ReadData(func(chunk Chunk) error {
+		for _, channel := range chunk.Channels {
+			if channel.Path != path {
+				continue
+			}
+			if first {
+				data.FirstSample = channel.SampleOffset
+				data.Shape = channel.Shape
+				first = false
+			}
+			data.Samples = append(data.Samples, channel.Samples...)
+			for _, t := range channel.TimeAxis().AbsoluteTimes() {
+				data.Times = append(data.Times, t)
+			}
+		}
+		return nil
+	}, append(options, WithTimeRange(from, to))...)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,109 @@
+package tdms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngyewch/tdms-go/internal/testfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeRange(t *testing.T) {
+	startTime := NewTimestamp(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	{
+		bound, err := ParseTimeBound("1.5")
+		if assert.NoError(t, err) {
+			assert.True(t, bound.Relative)
+			assert.Equal(t, 1.5, bound.Offset)
+		}
+		bound, err = ParseTimeBound("2024-01-01T00:00:01Z")
+		if assert.NoError(t, err) {
+			assert.False(t, bound.Relative)
+			assert.Equal(t, startTime.AddSeconds(1), bound.Time)
+		}
+	}
+	{
+		// segment restarting at 10s, containing samples 100 to 109 at 1 Hz
+		timing := segmentTiming{
+			sampleIndex: 100,
+			sampleCount: 10,
+			startTime:   startTime.AddSeconds(10),
+			waveformAttributes: &WaveformAttributes{
+				Increment: 1,
+			},
+		}
+		options := newReadOptions(WithTimeRange(RelativeTimeBound(12.5), AbsoluteTimeBound(startTime.AddSeconds(15).Time())))
+		start, end := options.sampleRange(timing, startTime)
+		assert.Equal(t, uint64(103), start)
+		assert.Equal(t, uint64(105), end)
+
+		options = newReadOptions(WithTimeRange(nil, RelativeTimeBound(5)))
+		start, end = options.sampleRange(timing, startTime)
+		assert.Equal(t, start, end)
+
+		options = newReadOptions(WithTimeRange(RelativeTimeBound(5), nil))
+		start, end = options.sampleRange(timing, startTime)
+		assert.Equal(t, uint64(100), start)
+		assert.Equal(t, uint64(110), end)
+	}
+}
+
+func TestReadTimeRange(t *testing.T) {
+	startTime := NewTimestamp(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	path := "/'g'/'a'"
+	file := openTestFile(t,
+		testfile.Segment{
+			ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData,
+			Objects: []testfile.Object{
+				{Path: "/"},
+				{Path: "/'g'"},
+				{Path: path, RawDataIndex: testfile.RawDataIndex(DataTypeDoubleFloat, 1, 3), Properties: testfile.WaveformProperties(testfile.Timestamp(startTime), 1)},
+			},
+			RawData: testfile.RawData([]float64{0, 1, 2}),
+		},
+		// a restart at 10s
+		testfile.Segment{
+			ToC: testfile.TocMetaData | testfile.TocRawData,
+			Objects: []testfile.Object{
+				{Path: path, RawDataIndex: testfile.RawDataIndexSameAsPreviousSegment, Properties: testfile.WaveformProperties(testfile.Timestamp(startTime.AddSeconds(10)), 1)},
+			},
+			RawData: testfile.RawData([]float64{10, 11, 12}),
+		},
+		// a segment at 100s, outside the time range, whose fixed-point raw data cannot be read without a format, and is skipped
+		testfile.Segment{
+			ToC: testfile.TocMetaData | testfile.TocNewObjList | testfile.TocRawData,
+			Objects: []testfile.Object{
+				{Path: "/'g'/'b'", RawDataIndex: testfile.RawDataIndex(uint32(DataTypeFixedPoint), 1, 1), Properties: testfile.WaveformProperties(testfile.Timestamp(startTime.AddSeconds(100)), 1)},
+			},
+			RawData: testfile.RawData(uint64(0)),
+		},
+	)
+	expectedTimes := []Timestamp{startTime.AddSeconds(1), startTime.AddSeconds(2), startTime.AddSeconds(10), startTime.AddSeconds(11)}
+	{
+		data, err := file.ReadTimeRange(path, startTime.AddSeconds(1).Time(), startTime.AddSeconds(11.5).Time())
+		if assert.NoError(t, err) {
+			assert.Equal(t, uint64(1), data.FirstSample)
+			assert.Equal(t, []float64{1, 2, 10, 11}, data.Samples)
+			assert.Equal(t, expectedTimes, data.Times)
+		}
+	}
+	{
+		data, err := file.ReadRelativeTimeRange(path, 1, 11.5)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []float64{1, 2, 10, 11}, data.Samples)
+			assert.Equal(t, expectedTimes, data.Times)
+		}
+	}
+	{
+		// only the first segment has samples in the time range
+		data, err := file.ReadTimeRange(path, time.Time{}, startTime.AddSeconds(5).Time())
+		if assert.NoError(t, err) {
+			assert.Equal(t, uint64(0), data.FirstSample)
+			assert.Equal(t, []float64{0, 1, 2}, data.Samples)
+		}
+	}
+	{
+		_, err := file.ReadTimeRange(path, time.Time{}, time.Time{})
+		assert.ErrorContains(t, err, "unsupported fixed-point layout")
+	}
+}
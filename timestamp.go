@@ -0,0 +1,237 @@
+package tdms
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"strings"
+	"time"
+)
+
+const (
+	// tdmsEpochUnixSeconds is the number of seconds between the TDMS epoch (1904-01-01 00:00:00 UTC) and the Unix epoch.
+	tdmsEpochUnixSeconds = -2082844800
+
+	nanosecondsPerSecond = 1_000_000_000
+
+	// timestampFractionDigits is the number of decimal digits needed to represent 2^-64 s exactly after a round trip.
+	timestampFractionDigits = 20
+)
+
+var (
+	twoPow64                = new(big.Int).Lsh(big.NewInt(1), 64)
+	timestampFractionFactor = new(big.Int).Exp(big.NewInt(10), big.NewInt(timestampFractionDigits), nil)
+)
+
+// Timestamp is a TDMS timestamp, i.e. the number of seconds since the TDMS epoch (1904-01-01 00:00:00 UTC)
+// together with the positive fractions (2^-64) of a second.
+type Timestamp struct {
+	Seconds  int64
+	Fraction uint64
+}
+
+// NewTimestamp converts a time.Time to a Timestamp.
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{
+		Seconds:  t.Unix() - tdmsEpochUnixSeconds,
+		Fraction: nanosecondsToFraction(uint64(t.Nanosecond())),
+	}
+}
+
+// nanosecondsToFraction converts nanoseconds (< 1s) to the nearest 2^-64 fractions of a second.
+func nanosecondsToFraction(nanoseconds uint64) uint64 {
+	quotient, remainder := bits.Div64(nanoseconds, 0, nanosecondsPerSecond)
+	if remainder >= nanosecondsPerSecond/2 {
+		quotient++
+	}
+	return quotient
+}
+
+// fractionToNanoseconds converts 2^-64 fractions of a second to the nearest nanoseconds. The result may be 1s.
+func fractionToNanoseconds(fraction uint64) uint64 {
+	hi, lo := bits.Mul64(fraction, nanosecondsPerSecond)
+	if lo >= 1<<63 {
+		hi++
+	}
+	return hi
+}
+
+// Time returns the timestamp as time.Time (UTC), rounded to the nearest nanosecond.
+func (ts Timestamp) Time() time.Time {
+	return time.Unix(ts.Seconds+tdmsEpochUnixSeconds, int64(fractionToNanoseconds(ts.Fraction))).UTC()
+}
+
+func (ts Timestamp) IsZero() bool {
+	return (ts.Seconds == 0) && (ts.Fraction == 0)
+}
+
+// Add returns the timestamp ts+d.
+func (ts Timestamp) Add(d time.Duration) Timestamp {
+	seconds := int64(d / time.Second)
+	nanoseconds := int64(d % time.Second)
+	if nanoseconds < 0 {
+		seconds--
+		nanoseconds += nanosecondsPerSecond
+	}
+	return ts.add(seconds, nanosecondsToFraction(uint64(nanoseconds)))
+}
+
+// AddSeconds returns the timestamp ts+seconds.
+func (ts Timestamp) AddSeconds(seconds float64) Timestamp {
+	wholeSeconds := math.Floor(seconds)
+	fraction, _ := new(big.Float).Mul(big.NewFloat(seconds-wholeSeconds), new(big.Float).SetInt(twoPow64)).Uint64()
+	return ts.add(int64(wholeSeconds), fraction)
+}
+
+func (ts Timestamp) add(seconds int64, fraction uint64) Timestamp {
+	sumFraction, carry := bits.Add64(ts.Fraction, fraction, 0)
+	return Timestamp{
+		Seconds:  ts.Seconds + seconds + int64(carry),
+		Fraction: sumFraction,
+	}
+}
+
+// sub returns ts-other as whole seconds and positive fractions of a second.
+func (ts Timestamp) sub(other Timestamp) (int64, uint64) {
+	fraction, borrow := bits.Sub64(ts.Fraction, other.Fraction, 0)
+	return ts.Seconds - other.Seconds - int64(borrow), fraction
+}
+
+// Sub returns the duration ts-other, rounded to the nearest nanosecond.
+func (ts Timestamp) Sub(other Timestamp) time.Duration {
+	seconds, fraction := ts.sub(other)
+	return time.Duration(seconds)*time.Second + time.Duration(fractionToNanoseconds(fraction))
+}
+
+// SubSeconds returns ts-other in seconds.
+func (ts Timestamp) SubSeconds(other Timestamp) float64 {
+	seconds, fraction := ts.sub(other)
+	return float64(seconds) + math.Ldexp(float64(fraction), -64)
+}
+
+// Compare returns -1 if ts is before other, 0 if ts equals other, or +1 if ts is after other.
+func (ts Timestamp) Compare(other Timestamp) int {
+	switch {
+	case ts.Seconds < other.Seconds:
+		return -1
+	case ts.Seconds > other.Seconds:
+		return 1
+	case ts.Fraction < other.Fraction:
+		return -1
+	case ts.Fraction > other.Fraction:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (ts Timestamp) Before(other Timestamp) bool {
+	return ts.Compare(other) < 0
+}
+
+func (ts Timestamp) After(other Timestamp) bool {
+	return ts.Compare(other) > 0
+}
+
+func (ts Timestamp) Equal(other Timestamp) bool {
+	return ts.Compare(other) == 0
+}
+
+// String returns the timestamp in ISO 8601 format (UTC) with full precision, e.g. 2024-01-01T00:00:00.5Z.
+func (ts Timestamp) String() string {
+	t := time.Unix(ts.Seconds+tdmsEpochUnixSeconds, 0).UTC()
+	s := t.Format("2006-01-02T15:04:05")
+	if ts.Fraction != 0 {
+		digits := new(big.Int).Mul(new(big.Int).SetUint64(ts.Fraction), timestampFractionFactor)
+		digits = roundedQuotient(digits, twoPow64)
+		s += "." + strings.TrimRight(fmt.Sprintf("%0*s", timestampFractionDigits, digits.String()), "0")
+	}
+	return s + "Z"
+}
+
+// Format returns the timestamp formatted according to the layout, see time.Time.Format.
+func (ts Timestamp) Format(layout string) string {
+	return ts.Time().Format(layout)
+}
+
+func (ts Timestamp) MarshalText() ([]byte, error) {
+	return []byte(ts.String()), nil
+}
+
+func (ts *Timestamp) UnmarshalText(text []byte) error {
+	parsed, err := ParseTimestamp(string(text))
+	if err != nil {
+		return err
+	}
+	*ts = parsed
+	return nil
+}
+
+// ParseTimestamp parses an RFC 3339 timestamp with an arbitrary number of fractional second digits.
+func ParseTimestamp(s string) (Timestamp, error) {
+	// split off the fractional seconds, which time.Parse would truncate to nanoseconds
+	fractionDigits := ""
+	withoutFraction := s
+	dotIndex := strings.IndexByte(s, '.')
+	if dotIndex >= 0 {
+		end := dotIndex + 1
+		for (end < len(s)) && (s[end] >= '0') && (s[end] <= '9') {
+			end++
+		}
+		fractionDigits = s[dotIndex+1 : end]
+		withoutFraction = s[:dotIndex] + s[end:]
+	}
+	t, err := time.Parse(time.RFC3339, withoutFraction)
+	if err != nil {
+		return Timestamp{}, err
+	}
+	ts := NewTimestamp(t)
+	if fractionDigits != "" {
+		numerator, ok := new(big.Int).SetString(fractionDigits, 10)
+		if !ok {
+			return Timestamp{}, fmt.Errorf("invalid fractional seconds: %s", fractionDigits)
+		}
+		denominator := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(len(fractionDigits))), nil)
+		fraction := roundedQuotient(numerator.Mul(numerator, twoPow64), denominator)
+		if fraction.Cmp(twoPow64) >= 0 {
+			ts.Seconds++
+			fraction.Sub(fraction, twoPow64)
+		}
+		ts.Fraction = fraction.Uint64()
+	}
+	return ts, nil
+}
+
+func roundedQuotient(numerator *big.Int, denominator *big.Int) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if remainder.Lsh(remainder, 1).Cmp(denominator) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	return quotient
+}
+
+// AsTimestamp converts a property value to a Timestamp.
+func AsTimestamp(v any) (Timestamp, error) {
+	switch v1 := v.(type) {
+	case Timestamp:
+		return v1, nil
+	case time.Time:
+		return NewTimestamp(v1), nil
+	default:
+		return Timestamp{}, fmt.Errorf("cannot convert %v to Timestamp", v)
+	}
+}
+
+// GetTimestamp returns the named property as a Timestamp.
+func GetTimestamp(props map[string]any, name string) (Timestamp, bool, error) {
+	v, exists := props[name]
+	if !exists {
+		return Timestamp{}, false, nil
+	}
+	ts, err := AsTimestamp(v)
+	if err != nil {
+		return Timestamp{}, true, err
+	}
+	return ts, true, nil
+}
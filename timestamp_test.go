@@ -0,0 +1,57 @@
+package tdms
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimestamp(t *testing.T) {
+	{
+		// 2024-01-01T00:00:00.5Z
+		b := []byte{0, 0, 0, 0, 0, 0, 0, 0x80, 0x00, 0xb1, 0xb7, 0xe1, 0, 0, 0, 0}
+		ts, err := LittleEndianValueReader.ReadTimestamp(bytes.NewReader(b))
+		if assert.NoError(t, err) {
+			assert.Equal(t, int64(3786912000), ts.Seconds)
+			assert.Equal(t, uint64(1<<63), ts.Fraction)
+			assert.Equal(t, time.Date(2024, time.January, 1, 0, 0, 0, 500_000_000, time.UTC), ts.Time())
+			assert.Equal(t, "2024-01-01T00:00:00.5Z", ts.String())
+		}
+	}
+	{
+		tm := time.Date(2025, time.December, 10, 15, 29, 16, 123456789, time.UTC)
+		ts := NewTimestamp(tm)
+		assert.Equal(t, tm, ts.Time())
+		assert.Equal(t, tm.Add(1500*time.Millisecond), ts.Add(1500*time.Millisecond).Time())
+		assert.Equal(t, tm.Add(-1500*time.Millisecond), ts.Add(-1500*time.Millisecond).Time())
+		assert.Equal(t, tm.Add(250*time.Millisecond), ts.AddSeconds(0.25).Time())
+		assert.Equal(t, 1500*time.Millisecond, ts.Add(1500*time.Millisecond).Sub(ts))
+		assert.Equal(t, -1500*time.Millisecond, ts.Sub(ts.Add(1500*time.Millisecond)))
+		assert.InDelta(t, 0.25, ts.AddSeconds(0.25).SubSeconds(ts), 1e-15)
+		assert.True(t, ts.Before(ts.AddSeconds(1e-12)))
+		assert.True(t, ts.AddSeconds(1e-12).After(ts))
+	}
+	{
+		// 2^-64 s precision is preserved when formatting and parsing
+		ts := Timestamp{Seconds: 3786912000, Fraction: 1}
+		s := ts.String()
+		assert.Equal(t, "2024-01-01T00:00:00.00000000000000000005Z", s)
+		parsed, err := ParseTimestamp(s)
+		if assert.NoError(t, err) {
+			assert.Equal(t, ts, parsed)
+		}
+	}
+	{
+		ts := Timestamp{Seconds: 3786912000, Fraction: 0xfedcba9876543210}
+		parsed, err := ParseTimestamp(ts.String())
+		if assert.NoError(t, err) {
+			assert.Equal(t, ts, parsed)
+		}
+		parsed, err = ParseTimestamp("2024-01-01T08:00:00.5+08:00")
+		if assert.NoError(t, err) {
+			assert.Equal(t, Timestamp{Seconds: 3786912000, Fraction: 1 << 63}, parsed)
+		}
+	}
+}
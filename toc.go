@@ -1,7 +1,36 @@
 package tdms
 
+import "strings"
+
 type TableOfContents uint32
 
+var tableOfContentsFlags = []struct {
+	mask TableOfContents
+	name string
+}{
+	{1 << 1, "MetaData"},
+	{1 << 2, "NewObjList"},
+	{1 << 3, "RawData"},
+	{1 << 5, "InterleavedData"},
+	{1 << 6, "BigEndian"},
+	{1 << 7, "DAQmxRawData"},
+}
+
+// Flags returns the names of the flags set in the table of contents.
+func (toc TableOfContents) Flags() []string {
+	var flags []string
+	for _, flag := range tableOfContentsFlags {
+		if toc&flag.mask != 0 {
+			flags = append(flags, flag.name)
+		}
+	}
+	return flags
+}
+
+func (toc TableOfContents) String() string {
+	return strings.Join(toc.Flags(), "|")
+}
+
 func (toc TableOfContents) MetaData() bool {
 	return toc&(1<<1) != 0
 }
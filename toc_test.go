@@ -0,0 +1,38 @@
+package tdms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableOfContents(t *testing.T) {
+	for _, test := range []struct {
+		toc    TableOfContents
+		flags  []string
+		string string
+	}{
+		{0, nil, ""},
+		{1 << 1, []string{"MetaData"}, "MetaData"},
+		{1<<1 | 1<<2 | 1<<3, []string{"MetaData", "NewObjList", "RawData"}, "MetaData|NewObjList|RawData"},
+		{1<<3 | 1<<5, []string{"RawData", "InterleavedData"}, "RawData|InterleavedData"},
+		{1<<6 | 1<<7, []string{"BigEndian", "DAQmxRawData"}, "BigEndian|DAQmxRawData"},
+		// bits without a flag are ignored
+		{1 | 1<<4 | 1<<8 | 1<<3, []string{"RawData"}, "RawData"},
+		{0xffffffff, []string{"MetaData", "NewObjList", "RawData", "InterleavedData", "BigEndian", "DAQmxRawData"}, "MetaData|NewObjList|RawData|InterleavedData|BigEndian|DAQmxRawData"},
+	} {
+		assert.Equal(t, test.flags, test.toc.Flags(), "toc %#x", uint32(test.toc))
+		assert.Equal(t, test.string, test.toc.String(), "toc %#x", uint32(test.toc))
+	}
+	{
+		toc := TableOfContents(1<<1 | 1<<2 | 1<<3 | 1<<5 | 1<<6 | 1<<7)
+		assert.True(t, toc.MetaData())
+		assert.True(t, toc.NewObjList())
+		assert.True(t, toc.RawData())
+		assert.True(t, toc.InterleavedData())
+		assert.True(t, toc.BigEndian())
+		assert.True(t, toc.DAQmxRawData())
+		assert.Same(t, BigEndianValueReader, toc.ValueReader())
+		assert.Same(t, LittleEndianValueReader, TableOfContents(0).ValueReader())
+	}
+}
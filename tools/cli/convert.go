@@ -3,13 +3,30 @@ package main
 import (
 	"context"
 	"fmt"
-	"path/filepath"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 
+	"github.com/ngyewch/tdms-go"
 	"github.com/ngyewch/tdms-go/converter"
 	"github.com/urfave/cli/v3"
 )
 
+// formatOptionFlags holds the flags of the options that are specific to some formats, see converter.Format.Options.
+var formatOptionFlags = []cli.Flag{
+	timeFlag,
+	delimiterFlag,
+	floatFormatFlag,
+	unitsFlag,
+}
+
 func doConvert(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Bool(listFormatsFlag.Name) {
+		return listFormats()
+	}
+
 	inputFile := cmd.StringArg(inputFileArg.Name)
 	outputFile := cmd.StringArg(outputFileArg.Name)
 
@@ -20,33 +37,200 @@ func doConvert(ctx context.Context, cmd *cli.Command) error {
 		return fmt.Errorf("output file is required")
 	}
 
-	outputExtension := filepath.Ext(outputFile)
-	switch outputExtension {
-	case ".mat":
-		return doConvertToMAT(ctx, inputFile, outputFile)
-	case ".h5", ".hdf5":
-		return doConvertToHDF5(ctx, inputFile, outputFile)
-	case ".cdl":
-		return doConvertToCDL(ctx, inputFile, outputFile)
-	case ".nc":
-		return doConvertToNetCDF4(ctx, inputFile, outputFile)
+	options := converter.Options{
+		IncludeTime: cmd.Bool(timeFlag.Name),
+		SortByName:  cmd.Bool(sortFlag.Name),
+		FloatFormat: cmd.String(floatFormatFlag.Name),
+		UnitRow:     cmd.Bool(unitsFlag.Name),
+		Decimation:  cmd.Uint64(decimateFlag.Name),
+		Raw:         cmd.Bool(rawFlag.Name),
+	}
+	delimiter := cmd.String(delimiterFlag.Name)
+	if delimiter != "" {
+		if delimiter == "tab" {
+			delimiter = "\t"
+		}
+		runes := []rune(delimiter)
+		if len(runes) != 1 {
+			return fmt.Errorf("delimiter must be a single character")
+		}
+		options.Delimiter = runes[0]
+	}
+	calibrationFile := cmd.String(calibrationFlag.Name)
+	if calibrationFile != "" {
+		calibration, err := tdms.ReadCalibrationFile(calibrationFile)
+		if err != nil {
+			return err
+		}
+		options.Calibration = calibration
+	}
+
+	if cmd.IsSet(resampleRateFlag.Name) || cmd.IsSet(resampleMethodFlag.Name) {
+		options.Resample = true
+		options.ResampleRate = cmd.Float(resampleRateFlag.Name)
+		options.ResampleMethod = tdms.ResampleLinear
+		resampleMethod := cmd.String(resampleMethodFlag.Name)
+		if resampleMethod != "" {
+			resampleMethod, err := tdms.ParseResampleMethod(resampleMethod)
+			if err != nil {
+				return err
+			}
+			options.ResampleMethod = resampleMethod
+		}
+	}
+
+	from := cmd.String(fromFlag.Name)
+	if from != "" {
+		timeBound, err := tdms.ParseTimeBound(from)
+		if err != nil {
+			return err
+		}
+		options.From = timeBound
+	}
+	to := cmd.String(toFlag.Name)
+	if to != "" {
+		timeBound, err := tdms.ParseTimeBound(to)
+		if err != nil {
+			return err
+		}
+		options.To = timeBound
+	}
+
+	include, err := parseChannelPatterns(cmd.StringSlice(includeFlag.Name))
+	if err != nil {
+		return err
+	}
+	options.Include = include
+	exclude, err := parseChannelPatterns(cmd.StringSlice(excludeFlag.Name))
+	if err != nil {
+		return err
+	}
+	options.Exclude = exclude
+	samples := cmd.String(samplesFlag.Name)
+	if samples != "" {
+		start, end, err := parseSampleRange(samples)
+		if err != nil {
+			return err
+		}
+		options.StartSample = start
+		options.EndSample = end
+	}
+	switch valueType := cmd.String(valueTypeFlag.Name); valueType {
+	case "":
+	case "float32":
+		options.ValueType = tdms.DataTypeSingleFloat
+	case "float64":
+		options.ValueType = tdms.DataTypeDoubleFloat
 	default:
-		return fmt.Errorf("unsupported output file extension")
+		return fmt.Errorf("unknown value type %s", valueType)
+	}
+
+	var format converter.Format
+	formatName := cmd.String(outputFormatFlag.Name)
+	if formatName != "" {
+		var exists bool
+		format, exists = converter.LookupFormat(formatName)
+		if !exists {
+			return fmt.Errorf("unknown format %s", formatName)
+		}
+	} else {
+		var exists bool
+		format, exists = converter.LookupFormatForFile(outputFile)
+		if !exists {
+			return fmt.Errorf("unsupported output file extension")
+		}
+	}
+	err = checkFormatOptions(format, cmd.IsSet)
+	if err != nil {
+		return err
 	}
+
+	return converter.Convert(inputFile, outputFile, format, options)
 }
 
-func doConvertToHDF5(ctx context.Context, inputFile string, outputFile string) error {
-	return converter.ConvertToHDF5(inputFile, outputFile)
+// checkFormatOptions rejects the set format option flags that the format does not support.
+func checkFormatOptions(format converter.Format, isSet func(name string) bool) error {
+	for _, flag := range formatOptionFlags {
+		name := flag.Names()[0]
+		if isSet(name) && !slices.ContainsFunc(format.Options(), func(optionInfo converter.OptionInfo) bool {
+			return optionInfo.Name == name
+		}) {
+			return fmt.Errorf("option --%s is not supported by format %s", name, format.Name())
+		}
+	}
+	return nil
 }
 
-func doConvertToMAT(ctx context.Context, inputFile string, outputFile string) error {
-	return converter.ConvertToMAT(inputFile, outputFile)
+// parseChannelPatterns parses GROUP[/CHANNEL] glob patterns, see parseChannelPattern.
+func parseChannelPatterns(patterns []string) ([]tdms.ChannelFilter, error) {
+	var filters []tdms.ChannelFilter
+	for _, pattern := range patterns {
+		filter, err := parseChannelPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
 }
 
-func doConvertToCDL(ctx context.Context, inputFile string, outputFile string) error {
-	return converter.ConvertToCDL(inputFile, outputFile)
+// parseChannelPattern parses a GROUP[/CHANNEL] glob pattern. A pattern without a channel matches all channels of the matching groups.
+// The group and channel patterns are separated by the first "/" that is not escaped, so a "/" in a group name is matched by "\/",
+// e.g. "a\/b/ch*" matches the channels ch* of the group a/b. As defined by path.Match, "*" and "?" do not match "/".
+func parseChannelPattern(pattern string) (tdms.ChannelFilter, error) {
+	groupPattern, channelPattern := pattern, ""
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '\\' {
+			i++
+		} else if pattern[i] == '/' {
+			groupPattern, channelPattern = pattern[:i], pattern[i+1:]
+			break
+		}
+	}
+	return tdms.MatchGlob(groupPattern, channelPattern)
 }
 
-func doConvertToNetCDF4(ctx context.Context, inputFile string, outputFile string) error {
-	return converter.ConvertToNetCDF4(inputFile, outputFile)
+// parseSampleRange parses a START:END range of sample indexes. An omitted END is returned as 0, i.e. unbounded.
+func parseSampleRange(s string) (uint64, uint64, error) {
+	startString, endString, found := strings.Cut(s, ":")
+	if !found {
+		return 0, 0, fmt.Errorf("sample range must be START:END")
+	}
+	var start, end uint64
+	var err error
+	if startString != "" {
+		start, err = strconv.ParseUint(startString, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid sample range start: %w", err)
+		}
+	}
+	if endString != "" {
+		end, err = strconv.ParseUint(endString, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid sample range end: %w", err)
+		}
+		if end <= start {
+			return 0, 0, fmt.Errorf("sample range end must be greater than start")
+		}
+	}
+	return start, end, nil
+}
+
+func listFormats() error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, err := fmt.Fprintln(w, "NAME\tEXTENSIONS\tOPTIONS\tDESCRIPTION")
+	if err != nil {
+		return err
+	}
+	for _, format := range converter.Formats() {
+		var options []string
+		for _, optionInfo := range format.Options() {
+			options = append(options, optionInfo.Name)
+		}
+		_, err = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", format.Name(), strings.Join(format.Extensions(), " "), strings.Join(options, " "), format.Description())
+		if err != nil {
+			return err
+		}
+	}
+	return w.Flush()
 }
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ngyewch/tdms-go"
+	"github.com/ngyewch/tdms-go/converter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckFormatOptions(t *testing.T) {
+	isSet := func(names ...string) func(name string) bool {
+		return func(name string) bool {
+			return slices.Contains(names, name)
+		}
+	}
+	csvFormat, _ := converter.LookupFormat("csv")
+	parquetFormat, _ := converter.LookupFormat("parquet")
+	zarrFormat, _ := converter.LookupFormat("zarr")
+
+	assert.NoError(t, checkFormatOptions(csvFormat, isSet("time", "delimiter", "float-format", "units")))
+	assert.NoError(t, checkFormatOptions(parquetFormat, isSet("time", "sort", "include")))
+	assert.NoError(t, checkFormatOptions(zarrFormat, isSet()))
+	{
+		err := checkFormatOptions(parquetFormat, isSet("time", "delimiter"))
+		if assert.Error(t, err) {
+			assert.Equal(t, "option --delimiter is not supported by format parquet", err.Error())
+		}
+	}
+	{
+		err := checkFormatOptions(zarrFormat, isSet("time"))
+		if assert.Error(t, err) {
+			assert.Equal(t, "option --time is not supported by format zarr", err.Error())
+		}
+	}
+}
+
+func TestParseChannelPattern(t *testing.T) {
+	root := tdms.NewNode("", "/")
+	var channels []*tdms.Node
+	for _, objectPath := range []tdms.ObjectPath{
+		{Group: "a/b", Channel: "ch1"},
+		{Group: "a", Channel: "b/ch1"},
+		{Group: "a", Channel: "ch2"},
+		{Group: "g", Channel: "ch1"},
+	} {
+		group := root.GetChildByName(objectPath.Group)
+		if group == nil {
+			group = tdms.NewNode(objectPath.Group, tdms.ObjectPath{Group: objectPath.Group}.String())
+			root.AddChild(group)
+		}
+		channel := tdms.NewNode(objectPath.Channel, objectPath.String())
+		group.AddChild(channel)
+		channels = append(channels, channel)
+	}
+	matches := func(patterns ...string) []string {
+		filters, err := parseChannelPatterns(patterns)
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		var paths []string
+		for _, channel := range channels {
+			for _, filter := range filters {
+				if filter(channel) {
+					paths = append(paths, channel.Path())
+					break
+				}
+			}
+		}
+		return paths
+	}
+	assert.Equal(t, []string{"/'a'/'b/ch1'", "/'a'/'ch2'"}, matches("a"))
+	assert.Equal(t, []string{"/'a'/'b/ch1'"}, matches("a/b/ch1"))
+	assert.Equal(t, []string{"/'a/b'/'ch1'"}, matches(`a\/b/ch1`))
+	assert.Equal(t, []string{"/'a/b'/'ch1'"}, matches(`a\/b`))
+	assert.Equal(t, []string{"/'a/b'/'ch1'", "/'g'/'ch1'"}, matches("*/ch1", `a\/*`))
+	assert.Equal(t, []string{"/'a'/'ch2'", "/'g'/'ch1'"}, matches("?/ch*"))
+	assert.Nil(t, matches())
+
+	_, err := parseChannelPatterns([]string{"g", "[/x"})
+	assert.Error(t, err)
+}
+
+func TestParseSampleRange(t *testing.T) {
+	for s, expected := range map[string][2]uint64{
+		"10:20": {10, 20},
+		":20":   {0, 20},
+		"10:":   {10, 0},
+		":":     {0, 0},
+		"0:1":   {0, 1},
+	} {
+		start, end, err := parseSampleRange(s)
+		if assert.NoError(t, err, s) {
+			assert.Equal(t, expected, [2]uint64{start, end}, s)
+		}
+	}
+	for s, message := range map[string]string{
+		"10":    "sample range must be START:END",
+		"x:20":  `invalid sample range start: strconv.ParseUint: parsing "x": invalid syntax`,
+		"10:-1": `invalid sample range end: strconv.ParseUint: parsing "-1": invalid syntax`,
+		"20:10": "sample range end must be greater than start",
+		"10:10": "sample range end must be greater than start",
+	} {
+		_, _, err := parseSampleRange(s)
+		if assert.Error(t, err, s) {
+			assert.Equal(t, message, err.Error(), s)
+		}
+	}
+}
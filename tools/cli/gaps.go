@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ngyewch/tdms-go"
+	"github.com/urfave/cli/v3"
+)
+
+func doGaps(ctx context.Context, cmd *cli.Command) error {
+	inputFile := cmd.StringArg(inputFileArg.Name)
+
+	if inputFile == "" {
+		return fmt.Errorf("input file is required")
+	}
+
+	tdmsFile, err := tdms.OpenFile(inputFile)
+	if err != nil {
+		return err
+	}
+	defer func(tdmsFile *tdms.File) {
+		_ = tdmsFile.Close()
+	}(tdmsFile)
+
+	discontinuities, err := tdmsFile.FindDiscontinuities(cmd.Float(toleranceFlag.Name))
+	if err != nil {
+		return err
+	}
+	printDiscontinuities(os.Stdout, discontinuities)
+	return nil
+}
+
+// printDiscontinuities prints one line for each discontinuity, followed by the number of discontinuities.
+func printDiscontinuities(w io.Writer, discontinuities []tdms.Discontinuity) {
+	for _, discontinuity := range discontinuities {
+		switch discontinuity.Type {
+		case tdms.DiscontinuitySampleCountMismatch:
+			_, _ = fmt.Fprintf(w, "%s: segment %d (offset %d), sample %d: %s, expected %d samples, found %d\n",
+				discontinuity.Path, discontinuity.SegmentIndex, discontinuity.SegmentOffset, discontinuity.SampleIndex,
+				discontinuity.Type, discontinuity.ExpectedSampleCount, discontinuity.SampleCount)
+		default:
+			_, _ = fmt.Fprintf(w, "%s: segment %d (offset %d), sample %d: %s of %gs, expected start %s, found %s\n",
+				discontinuity.Path, discontinuity.SegmentIndex, discontinuity.SegmentOffset, discontinuity.SampleIndex,
+				discontinuity.Type, discontinuity.Duration, discontinuity.ExpectedStartTime, discontinuity.StartTime)
+		}
+	}
+	_, _ = fmt.Fprintf(w, "%d discontinuities found\n", len(discontinuities))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ngyewch/tdms-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintDiscontinuities(t *testing.T) {
+	startTime := tdms.NewTimestamp(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	{
+		var sb strings.Builder
+		printDiscontinuities(&sb, []tdms.Discontinuity{
+			{
+				Type:                tdms.DiscontinuitySampleCountMismatch,
+				Path:                "/'g'/'a'",
+				StartTime:           startTime,
+				ExpectedSampleCount: 3,
+				SampleCount:         2,
+			},
+			{
+				Type:              tdms.DiscontinuityGap,
+				Path:              "/'g'/'a'",
+				SegmentIndex:      2,
+				SegmentOffset:     312,
+				SampleIndex:       4,
+				ExpectedStartTime: startTime.AddSeconds(4),
+				StartTime:         startTime.AddSeconds(6),
+				Duration:          2,
+			},
+			{
+				Type:              tdms.DiscontinuityOverlap,
+				Path:              "/'g'/'a'",
+				SegmentIndex:      3,
+				SegmentOffset:     468,
+				SampleIndex:       6,
+				ExpectedStartTime: startTime.AddSeconds(8),
+				StartTime:         startTime.AddSeconds(7.75),
+				Duration:          -0.25,
+			},
+		})
+		assert.Equal(t, `/'g'/'a': segment 0 (offset 0), sample 0: sample-count-mismatch, expected 3 samples, found 2
+/'g'/'a': segment 2 (offset 312), sample 4: gap of 2s, expected start 2024-01-01T00:00:04Z, found 2024-01-01T00:00:06Z
+/'g'/'a': segment 3 (offset 468), sample 6: overlap of -0.25s, expected start 2024-01-01T00:00:08Z, found 2024-01-01T00:00:07.75Z
+3 discontinuities found
+`, sb.String())
+	}
+	{
+		var sb strings.Builder
+		printDiscontinuities(&sb, nil)
+		assert.Equal(t, "0 discontinuities found\n", sb.String())
+	}
+}
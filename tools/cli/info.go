@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ngyewch/tdms-go"
+	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
+)
+
+func doInfo(ctx context.Context, cmd *cli.Command) error {
+	inputFile := cmd.StringArg(inputFileArg.Name)
+
+	if inputFile == "" {
+		return fmt.Errorf("input file is required")
+	}
+
+	tdmsFile, err := tdms.OpenFile(inputFile)
+	if err != nil {
+		return err
+	}
+	defer func(tdmsFile *tdms.File) {
+		_ = tdmsFile.Close()
+	}(tdmsFile)
+
+	fileInfo, err := tdmsFile.Info()
+	if err != nil {
+		return err
+	}
+
+	return printFormatted(cmd.String(formatFlag.Name), fileInfo, func() error {
+		printInfo(fileInfo)
+		return nil
+	})
+}
+
+// printFormatted prints v as JSON or YAML, or using printHuman for the human format.
+func printFormatted(format string, v any, printHuman func() error) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case "yaml":
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		err := encoder.Encode(v)
+		if err != nil {
+			return err
+		}
+		return encoder.Close()
+	case "human", "":
+		return printHuman()
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func printInfo(fileInfo *tdms.FileInfo) {
+	fmt.Println("/")
+	printProperties(fileInfo.Properties, "  ")
+	for _, group := range fileInfo.Groups {
+		fmt.Printf("- %s\n", group.Name)
+		printProperties(group.Properties, "    ")
+		for _, channel := range group.Channels {
+			fmt.Printf("  - %s: %s\n", channel.Name, channelSummary(channel))
+			printProperties(channel.Properties, "      ")
+		}
+	}
+}
+
+func channelSummary(channel tdms.ChannelInfo) string {
+	var parts []string
+	if channel.DataType != "" {
+		dataType := channel.DataType
+		if channel.ArrayDimension > 1 {
+			dataType += fmt.Sprintf("[%d]", channel.ArrayDimension)
+		}
+		parts = append(parts, dataType)
+	}
+	parts = append(parts, fmt.Sprintf("%d samples", channel.SampleCount))
+	if channel.SampleRate > 0 {
+		parts = append(parts, fmt.Sprintf("%g Hz", channel.SampleRate), fmt.Sprintf("%g s", channel.Duration))
+	}
+	if channel.StartTime != nil {
+		parts = append(parts, fmt.Sprintf("starting %s", channel.StartTime))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func printProperties(properties []tdms.PropertyInfo, indent string) {
+	for _, property := range properties {
+		fmt.Printf("%s* %s: %v [%s]\n", indent, property.Name, property.Value, property.Type)
+	}
+}
@@ -22,6 +22,107 @@ var (
 		UsageText: "(output file)",
 	}
 
+	calibrationFlag = &cli.StringFlag{
+		Name:  "calibration",
+		Usage: "calibration file (YAML/JSON)",
+	}
+
+	timeFlag = &cli.BoolFlag{
+		Name:  "time",
+		Usage: "include time coordinates",
+	}
+
+	resampleRateFlag = &cli.FloatFlag{
+		Name:  "resample-rate",
+		Usage: "resample all channels onto a common time base at the specified rate (Hz); 0 uses the rate of the fastest channel",
+	}
+
+	resampleMethodFlag = &cli.StringFlag{
+		Name:  "resample-method",
+		Usage: "resample method (zoh, linear, polyphase); enables resampling",
+	}
+
+	fromFlag = &cli.StringFlag{
+		Name:  "from",
+		Usage: "start of time range (inclusive), as ISO 8601 timestamp or seconds relative to the first sample",
+	}
+
+	toFlag = &cli.StringFlag{
+		Name:  "to",
+		Usage: "end of time range (exclusive), as ISO 8601 timestamp or seconds relative to the first sample",
+	}
+
+	includeFlag = &cli.StringSliceFlag{
+		Name:  "include",
+		Usage: "include the channels matching GROUP[/CHANNEL] glob patterns, with / in group names escaped as \\/; defaults to all channels",
+	}
+
+	excludeFlag = &cli.StringSliceFlag{
+		Name:  "exclude",
+		Usage: "exclude the channels matching GROUP[/CHANNEL] glob patterns, with / in group names escaped as \\/",
+	}
+
+	samplesFlag = &cli.StringFlag{
+		Name:  "samples",
+		Usage: "range of sample indexes START:END (END exclusive); either bound may be omitted",
+	}
+
+	decimateFlag = &cli.Uint64Flag{
+		Name:  "decimate",
+		Usage: "keep only every n-th sample of each channel",
+	}
+
+	rawFlag = &cli.BoolFlag{
+		Name:  "raw",
+		Usage: "write raw values, without applying scalers or calibration",
+	}
+
+	valueTypeFlag = &cli.StringFlag{
+		Name:  "value-type",
+		Usage: "type of sample values (float32, float64); defaults to the native type where the format supports it, float64 otherwise",
+	}
+
+	sortFlag = &cli.BoolFlag{
+		Name:  "sort",
+		Usage: "order groups, channels and properties by name instead of file order",
+	}
+
+	delimiterFlag = &cli.StringFlag{
+		Name:  "delimiter",
+		Usage: "column delimiter of CSV/TSV output (a single character, or \"tab\")",
+	}
+
+	floatFormatFlag = &cli.StringFlag{
+		Name:  "float-format",
+		Usage: "format of sample values in CSV/TSV output, e.g. %.6f",
+	}
+
+	unitsFlag = &cli.BoolFlag{
+		Name:  "units",
+		Usage: "add a header row with units to CSV/TSV output",
+	}
+
+	formatFlag = &cli.StringFlag{
+		Name:  "format",
+		Usage: "output format (human, json, yaml)",
+		Value: "human",
+	}
+
+	outputFormatFlag = &cli.StringFlag{
+		Name:  "format",
+		Usage: "output format (see --list-formats); defaults to the format of the output file extension",
+	}
+
+	listFormatsFlag = &cli.BoolFlag{
+		Name:  "list-formats",
+		Usage: "list the output formats and exit",
+	}
+
+	toleranceFlag = &cli.FloatFlag{
+		Name:  "tolerance",
+		Usage: "time tolerance (s); defaults to half the channel's increment",
+	}
+
 	app = &cli.Command{
 		Name:    "tdms-cli",
 		Usage:   "TDMS CLI",
@@ -35,8 +136,61 @@ var (
 					inputFileArg,
 					outputFileArg,
 				},
+				Flags: []cli.Flag{
+					calibrationFlag,
+					timeFlag,
+					resampleRateFlag,
+					resampleMethodFlag,
+					fromFlag,
+					toFlag,
+					includeFlag,
+					excludeFlag,
+					samplesFlag,
+					decimateFlag,
+					rawFlag,
+					valueTypeFlag,
+					sortFlag,
+					delimiterFlag,
+					floatFormatFlag,
+					unitsFlag,
+					outputFormatFlag,
+					listFormatsFlag,
+				},
 				Action: doConvert,
 			},
+			{
+				Name:  "gaps",
+				Usage: "report time discontinuities and sample count mismatches",
+				Arguments: []cli.Argument{
+					inputFileArg,
+				},
+				Flags: []cli.Flag{
+					toleranceFlag,
+				},
+				Action: doGaps,
+			},
+			{
+				Name:  "info",
+				Usage: "print groups, channels and properties",
+				Arguments: []cli.Argument{
+					inputFileArg,
+				},
+				Flags: []cli.Flag{
+					formatFlag,
+				},
+				Action: doInfo,
+			},
+			{
+				Name:  "segments",
+				Usage: "dump segments",
+				Arguments: []cli.Argument{
+					inputFileArg,
+				},
+				Flags: []cli.Flag{
+					formatFlag,
+				},
+				Action: doSegments,
+			},
 			{
 				Name:  "test",
 				Usage: "test",
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ngyewch/tdms-go"
+	"github.com/urfave/cli/v3"
+)
+
+func doSegments(ctx context.Context, cmd *cli.Command) error {
+	inputFile := cmd.StringArg(inputFileArg.Name)
+
+	if inputFile == "" {
+		return fmt.Errorf("input file is required")
+	}
+
+	tdmsFile, err := tdms.OpenFile(inputFile)
+	if err != nil {
+		return err
+	}
+	defer func(tdmsFile *tdms.File) {
+		_ = tdmsFile.Close()
+	}(tdmsFile)
+
+	segmentInfos, err := tdmsFile.SegmentInfos()
+	if err != nil {
+		return err
+	}
+
+	return printFormatted(cmd.String(formatFlag.Name), segmentInfos, func() error {
+		return printSegments(segmentInfos)
+	})
+}
+
+func printSegments(segmentInfos []tdms.SegmentInfo) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, err := fmt.Fprintln(w, "INDEX\tOFFSET\tTYPE\tVERSION\tTOC\tRAW DATA OFFSET\tNEXT SEGMENT OFFSET\tRAW DATA SIZE\tOBJECTS")
+	if err != nil {
+		return err
+	}
+	for _, segmentInfo := range segmentInfos {
+		objects := fmt.Sprintf("%d", len(segmentInfo.Objects))
+		if segmentInfo.MetaDataInherited {
+			objects += " (inherited)"
+		}
+		_, err = fmt.Fprintf(w, "%d\t%d\t%s\t%d\t%s\t%d\t%d\t%d\t%s\n",
+			segmentInfo.Index, segmentInfo.Offset, segmentInfo.Type, segmentInfo.VersionNumber,
+			strings.Join(segmentInfo.Flags, "|"), segmentInfo.RawDataOffset, segmentInfo.NextSegmentOffset,
+			segmentInfo.RawDataSize, objects)
+		if err != nil {
+			return err
+		}
+	}
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	for _, segmentInfo := range segmentInfos {
+		if len(segmentInfo.Objects) == 0 {
+			continue
+		}
+		fmt.Printf("\nsegment %d objects:\n", segmentInfo.Index)
+		_, err = fmt.Fprintln(w, "PATH\tPROPERTIES\tINDEX\tDATA TYPE\tDIMENSION\tCHUNK SIZE\tSIZE\tDETAILS")
+		if err != nil {
+			return err
+		}
+		for _, objectInfo := range segmentInfo.Objects {
+			properties := "-"
+			if !segmentInfo.MetaDataInherited {
+				properties = fmt.Sprintf("%d", len(objectInfo.PropertyNames))
+			}
+			rawDataIndexInfo := objectInfo.RawDataIndex
+			if rawDataIndexInfo == nil {
+				_, err = fmt.Fprintf(w, "%s\t%s\t-\n", objectInfo.Path, properties)
+			} else {
+				_, err = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%s\n",
+					objectInfo.Path, properties, rawDataIndexInfo.Type, rawDataIndexInfo.DataType,
+					rawDataIndexInfo.ArrayDimension, rawDataIndexInfo.ChunkSize, rawDataIndexInfo.TotalSizeInBytes,
+					formatRawDataIndexDetails(rawDataIndexInfo))
+			}
+			if err != nil {
+				return err
+			}
+		}
+		err = w.Flush()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatRawDataIndexDetails(rawDataIndexInfo *tdms.RawDataIndexInfo) string {
+	var parts []string
+	if len(rawDataIndexInfo.RawDataWidths) > 0 {
+		parts = append(parts, fmt.Sprintf("rawDataWidths=%v", rawDataIndexInfo.RawDataWidths))
+	}
+	for _, scalerInfo := range rawDataIndexInfo.Scalers {
+		parts = append(parts, fmt.Sprintf("scaler[%d]=%s", scalerInfo.ScaleId, scalerInfo.Type))
+	}
+	return strings.Join(parts, " ")
+}
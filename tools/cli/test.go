@@ -31,28 +31,6 @@ func doTest(ctx context.Context, cmd *cli.Command) error {
 	}
 	fmt.Printf("sampleCount: %d\n", sampleCount)
 
-	if false {
-		fmt.Println("/")
-		tdmsFile.Root().Properties().All()(func(name string, value any) bool {
-			fmt.Printf("  * %s: %v [%T]\n", name, value, value)
-			return true
-		})
-		for _, group := range tdmsFile.Root().Children() {
-			fmt.Printf("- %s\n", group.Name())
-			group.Properties().All()(func(name string, value any) bool {
-				fmt.Printf("    * %s: %v [%T]\n", name, value, value)
-				return true
-			})
-			for _, channel := range group.Children() {
-				fmt.Printf("  - %s\n", channel.Name())
-				channel.Properties().All()(func(name string, value any) bool {
-					fmt.Printf("      * %s: %v [%T]\n", name, value, value)
-					return true
-				})
-			}
-		}
-	}
-
 	err = writeToWav(tdmsFile)
 	if err != nil {
 		return err
@@ -85,11 +85,22 @@ func AsFloat64(v any) (float64, error) {
 		return float64(v1), nil
 	case float64:
 		return v1, nil
+	case interface{ Float64() float64 }:
+		return v1.Float64(), nil
 	default:
 		return 0, fmt.Errorf("cannot convert %v to float64", v)
 	}
 }
 
+func AsBool(v any) (bool, error) {
+	switch v1 := v.(type) {
+	case bool:
+		return v1, nil
+	default:
+		return false, fmt.Errorf("cannot convert %v to bool", v)
+	}
+}
+
 func AsString(v any) (string, error) {
 	switch v1 := v.(type) {
 	case string:
@@ -103,6 +114,8 @@ func AsTime(v any) (time.Time, error) {
 	switch v1 := v.(type) {
 	case time.Time:
 		return v1, nil
+	case interface{ Time() time.Time }:
+		return v1.Time(), nil
 	default:
 		return time.Time{}, fmt.Errorf("cannot convert %v to time.Time", v)
 	}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// OrderedMap is a map that preserves the insertion order of its keys.
+type OrderedMap[K cmp.Ordered, V any] struct {
+	keys []K
+	m    map[K]V
+}
+
+func NewOrderedMap[K cmp.Ordered, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{
+		m: make(map[K]V),
+	}
+}
+
+// Insert sets the value of the key. A new key is appended; an existing key keeps its position.
+func (m *OrderedMap[K, V]) Insert(key K, value V) {
+	_, exists := m.m[key]
+	if !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.m[key] = value
+}
+
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	value, exists := m.m[key]
+	return value, exists
+}
+
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.keys)
+}
+
+// Keys returns the keys in insertion order.
+func (m *OrderedMap[K, V]) Keys() iter.Seq[K] {
+	return slices.Values(m.keys)
+}
+
+// Values returns the values in insertion order.
+func (m *OrderedMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, key := range m.keys {
+			if !yield(m.m[key]) {
+				return
+			}
+		}
+	}
+}
+
+// All returns the key-value pairs in insertion order.
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return m.all(m.keys)
+}
+
+// Sorted returns the key-value pairs in key order.
+func (m *OrderedMap[K, V]) Sorted() iter.Seq2[K, V] {
+	return m.all(slices.Sorted(slices.Values(m.keys)))
+}
+
+func (m *OrderedMap[K, V]) all(keys []K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, key := range keys {
+			if !yield(key, m.m[key]) {
+				return
+			}
+		}
+	}
+}
+
+// Collect returns a copy of the map as a Go map.
+func (m *OrderedMap[K, V]) Collect() map[K]V {
+	result := make(map[K]V, len(m.m))
+	for key, value := range m.m {
+		result[key] = value
+	}
+	return result
+}
+
+// CollectKeys returns the keys in insertion order.
+func (m *OrderedMap[K, V]) CollectKeys() []K {
+	return slices.Clone(m.keys)
+}
+
+// CollectValues returns the values in insertion order.
+func (m *OrderedMap[K, V]) CollectValues() []V {
+	return slices.Collect(m.Values())
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderedMap(t *testing.T) {
+	{
+		m := NewOrderedMap[string, int]()
+		m.Insert("ch2", 2)
+		m.Insert("ch10", 10)
+		m.Insert("ch1", 1)
+		m.Insert("ch2", 20)
+		assert.Equal(t, 3, m.Len())
+		assert.Equal(t, []string{"ch2", "ch10", "ch1"}, m.CollectKeys())
+		assert.Equal(t, []int{20, 10, 1}, m.CollectValues())
+		var sortedKeys []string
+		for key := range m.Sorted() {
+			sortedKeys = append(sortedKeys, key)
+		}
+		assert.Equal(t, []string{"ch1", "ch10", "ch2"}, sortedKeys)
+		v, exists := m.Get("ch10")
+		assert.True(t, exists)
+		assert.Equal(t, 10, v)
+		assert.Equal(t, map[string]int{"ch1": 1, "ch2": 20, "ch10": 10}, m.Collect())
+	}
+}
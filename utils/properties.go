@@ -40,6 +40,18 @@ func GetFloat64(props map[string]any, name string) (float64, bool, error) {
 	return n, true, nil
 }
 
+func GetBool(props map[string]any, name string) (bool, bool, error) {
+	v, exists := props[name]
+	if !exists {
+		return false, false, nil
+	}
+	b, err := AsBool(v)
+	if err != nil {
+		return false, true, err
+	}
+	return b, true, nil
+}
+
 func GetString(props map[string]any, name string) (string, bool, error) {
 	v, exists := props[name]
 	if !exists {
@@ -4,8 +4,6 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
-	"math"
-	"time"
 )
 
 var (
@@ -141,44 +139,33 @@ func (vr *ValueReader) ReadBoolean(r io.Reader) (bool, error) {
 	return v, nil
 }
 
-var (
-	tdmsEpoch = time.Date(1904, time.January, 1, 0, 0, 0, 0, time.UTC)
-	unixEpoch = time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC)
-)
-
-const (
-	fractionsPerNanosecond = (10 ^ -9) / (2 ^ -64)
-)
-
-func (vr *ValueReader) ReadTimestamp(r io.Reader) (time.Time, error) {
-	var seconds int64
-	var fractionalSeconds uint64
+func (vr *ValueReader) ReadTimestamp(r io.Reader) (Timestamp, error) {
+	var timestamp Timestamp
 	var err error
 
 	switch vr.byteOrder {
 	case binary.LittleEndian:
-		fractionalSeconds, err = vr.ReadU64(r)
+		timestamp.Fraction, err = vr.ReadU64(r)
 		if err != nil {
-			return time.Time{}, err
+			return Timestamp{}, err
 		}
-		seconds, err = vr.ReadI64(r)
+		timestamp.Seconds, err = vr.ReadI64(r)
 		if err != nil {
-			return time.Time{}, err
+			return Timestamp{}, err
 		}
 	case binary.BigEndian:
-		seconds, err = vr.ReadI64(r)
+		timestamp.Seconds, err = vr.ReadI64(r)
 		if err != nil {
-			return time.Time{}, err
+			return Timestamp{}, err
 		}
-		fractionalSeconds, err = vr.ReadU64(r)
+		timestamp.Fraction, err = vr.ReadU64(r)
 		if err != nil {
-			return time.Time{}, err
+			return Timestamp{}, err
 		}
 	default:
-		return time.Time{}, fmt.Errorf("unknown byte order")
+		return Timestamp{}, fmt.Errorf("unknown byte order")
 	}
-	nanoSeconds := float64(fractionalSeconds) * fractionsPerNanosecond
-	return time.Unix(seconds+tdmsEpoch.Unix()-unixEpoch.Unix(), int64(math.Round(nanoSeconds))), nil
+	return timestamp, nil
 }
 
 func (vr *ValueReader) ReadComplexSingleFloat(r io.Reader) (complex64, error) {
@@ -266,17 +253,23 @@ func (vr *ValueReader) ReadValueForDataType(r io.Reader, dataType DataType) (any
 		return vr.ReadSingleFloat(r)
 	case DataTypeDoubleFloat:
 		return vr.ReadDoubleFloat(r)
-	//case DataTypeExtendedFloat:
-	//case DataTypeSingleFloatWithUnit:
-	//case DataTypeDoubleFloatWithUnit:
-	//case DataTypeExtendedFloatWithUnit:
+	case DataTypeExtendedFloat:
+		return vr.ReadExtendedFloat(r)
+	case DataTypeSingleFloatWithUnit:
+		return vr.ReadSingleFloat(r)
+	case DataTypeDoubleFloatWithUnit:
+		return vr.ReadDoubleFloat(r)
+	case DataTypeExtendedFloatWithUnit:
+		return vr.ReadExtendedFloat(r)
 	case DataTypeString:
 		return vr.ReadString(r)
 	case DataTypeBoolean:
 		return vr.ReadBoolean(r)
 	case DataTypeTimestamp:
 		return vr.ReadTimestamp(r)
-	//case DataTypeFixedPoint:
+	case DataTypeFixedPoint:
+		// the layout of the fixed-point format stored with the value is not known
+		return nil, fmt.Errorf("unsupported fixed-point layout")
 	case DataTypeComplexSingleFloat:
 		return vr.ReadComplexSingleFloat(r)
 	case DataTypeComplexDoubleFloat:
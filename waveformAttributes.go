@@ -1,13 +1,11 @@
 package tdms
 
 import (
-	"time"
-
 	"github.com/ngyewch/tdms-go/utils"
 )
 
 type WaveformAttributes struct {
-	StartTime       time.Time
+	StartTime       Timestamp
 	StartOffset     float64
 	Increment       float64
 	Samples         int
@@ -19,8 +17,18 @@ func (waveformAttributes WaveformAttributes) SampleRate() float64 {
 	return 1 / float64(waveformAttributes.Increment)
 }
 
+// RelativeTime returns the time of the i-th sample, in seconds relative to the start time.
+func (waveformAttributes WaveformAttributes) RelativeTime(i uint64) float64 {
+	return waveformAttributes.StartOffset + float64(i)*waveformAttributes.Increment
+}
+
+// AbsoluteTime returns the time of the i-th sample.
+func (waveformAttributes WaveformAttributes) AbsoluteTime(i uint64) Timestamp {
+	return waveformAttributes.StartTime.AddSeconds(waveformAttributes.RelativeTime(i))
+}
+
 func GetWaveformAttributes(props map[string]any) (*WaveformAttributes, error) {
-	startTime, _, err := utils.GetTime(props, "wf_start_time")
+	startTime, _, err := GetTimestamp(props, "wf_start_time")
 	if err != nil {
 		return nil, err
 	}